@@ -0,0 +1,385 @@
+// Package httpfs implements fs.FS for OCFL objects published as static files
+// over HTTP(S).
+package httpfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srerickson/ocfl"
+)
+
+// ErrNotSupported is returned by ReadDir if the server doesn't provide a
+// JSON directory index.
+var ErrNotSupported = errors.New(`operation not supported by server`)
+
+// FS is an fs.FS for files served under a base URL. Files are read with GET
+// requests, Stat uses HEAD requests, and ReadAt uses range requests.
+// Directories are recognized by the server redirecting requests for them to
+// the same path with a trailing slash. Their entries can only be read if the server provides JSON directory
+// indexes in the format used by nginx's autoindex module: a GET request
+// for the directory path, with a trailing slash, returns a list of entries
+// with "name", "type" ("file" or "directory"), "size", and "mtime".
+type FS struct {
+	base    *url.URL
+	client  *http.Client
+	ctx     context.Context
+	retries int
+	backoff time.Duration
+}
+
+var _ fs.ReadDirFS = (*FS)(nil)
+var _ fs.StatFS = (*FS)(nil)
+var _ ocfl.CapabilitiesFS = (*FS)(nil)
+
+// Option is used to configure New
+type Option func(*FS)
+
+// WithClient sets the http.Client used for requests. The default is
+// http.DefaultClient.
+func WithClient(c *http.Client) Option {
+	return func(fsys *FS) {
+		fsys.client = c
+	}
+}
+
+// WithContext sets the context used for requests.
+func WithContext(ctx context.Context) Option {
+	return func(fsys *FS) {
+		fsys.ctx = ctx
+	}
+}
+
+// WithRetries sets the number of times requests are retried after a 5xx
+// response or a network error, and the delay before the first retry. The
+// delay doubles for each retry. The default is 3 retries starting at 100ms.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(fsys *FS) {
+		fsys.retries = n
+		fsys.backoff = backoff
+	}
+}
+
+// New returns a new FS for files under baseURL
+func New(baseURL string, opts ...Option) (*FS, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme != `http` && base.Scheme != `https` {
+		return nil, fmt.Errorf("unsupported URL scheme: %s", baseURL)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	fsys := &FS{
+		base:    base,
+		client:  http.DefaultClient,
+		ctx:     context.Background(),
+		retries: 3,
+		backoff: 100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(fsys)
+	}
+	return fsys, nil
+}
+
+// Open implements fs.FS
+func (fsys *FS) Open(name string) (fs.File, error) {
+	return fsys.OpenContext(fsys.ctx, name)
+}
+
+// OpenContext is like Open, but the request uses ctx instead of the FS's
+// context. Range requests made by the returned file, and listings for a
+// returned directory, also use ctx.
+func (fsys *FS) OpenContext(ctx context.Context, name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: `open`, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &dir{ctx: ctx, fsys: fsys, name: name}, nil
+	}
+	resp, err := fsys.do(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: `open`, Path: name, Err: err}
+	}
+	if isDir(resp) {
+		resp.Body.Close()
+		return &dir{ctx: ctx, fsys: fsys, name: name}, nil
+	}
+	return &file{
+		ctx:  ctx,
+		fsys: fsys,
+		name: name,
+		body: resp.Body,
+		info: newFileInfo(name, resp),
+	}, nil
+}
+
+// Capabilities implements ocfl.CapabilitiesFS. Files support range reads
+// and requests can be canceled. The FS is read-only, and directories can
+// only be listed one at a time.
+func (fsys *FS) Capabilities() ocfl.Capabilities {
+	return ocfl.Capabilities{
+		SupportsRangeReads: true,
+		SupportsContext:    true,
+	}
+}
+
+// Stat implements fs.StatFS
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	return fsys.StatContext(fsys.ctx, name)
+}
+
+// StatContext is like Stat, but the request uses ctx instead of the FS's
+// context.
+func (fsys *FS) StatContext(ctx context.Context, name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: `stat`, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &fileInfo{name: name, mode: fs.ModeDir}, nil
+	}
+	resp, err := fsys.do(ctx, http.MethodHead, name, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: `stat`, Path: name, Err: err}
+	}
+	resp.Body.Close()
+	info := newFileInfo(name, resp)
+	if isDir(resp) {
+		info.mode = fs.ModeDir
+		info.size = 0
+	}
+	return info, nil
+}
+
+// indexEntry is an entry in a JSON directory index
+type indexEntry struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Size  int64  `json:"size"`
+	MTime string `json:"mtime"`
+}
+
+// ReadDir implements fs.ReadDirFS. It returns an error wrapping
+// ErrNotSupported if the server doesn't provide a JSON index for the
+// directory.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fsys.ReadDirContext(fsys.ctx, name)
+}
+
+// ReadDirContext is like ReadDir, but the request uses ctx instead of the
+// FS's context.
+func (fsys *FS) ReadDirContext(ctx context.Context, name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: `readdir`, Path: name, Err: fs.ErrInvalid}
+	}
+	dirPath := name + "/"
+	if name == "." {
+		dirPath = ""
+	}
+	header := http.Header{"Accept": []string{"application/json"}}
+	resp, err := fsys.do(ctx, http.MethodGet, dirPath, header)
+	if err != nil {
+		return nil, &fs.PathError{Op: `readdir`, Path: name, Err: err}
+	}
+	defer resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return nil, &fs.PathError{Op: `readdir`, Path: name, Err: ErrNotSupported}
+	}
+	var index []indexEntry
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, &fs.PathError{Op: `readdir`, Path: name, Err: err}
+	}
+	entries := make([]fs.DirEntry, 0, len(index))
+	for _, e := range index {
+		info := &fileInfo{name: e.Name}
+		if e.Type == `directory` {
+			// size and mtime are ignored so entries match Stat, which
+			// doesn't have them for directories
+			info.mode = fs.ModeDir
+		} else {
+			info.size = e.Size
+			info.modTime, _ = time.Parse(time.RFC1123, e.MTime)
+		}
+		entries = append(entries, dirEntry{info})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// do sends a request for name, retrying after network errors and 5xx
+// responses. The response body must be closed by the caller. 404 responses
+// are returned as fs.ErrNotExist.
+func (fsys *FS) do(ctx context.Context, method string, name string, header http.Header) (*http.Response, error) {
+	u := *fsys.base
+	u.Path = fsys.base.Path + "/" + name
+	delay := fsys.backoff
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := fsys.client.Do(req)
+		retry := err != nil
+		if err == nil {
+			switch {
+			case resp.StatusCode == http.StatusNotFound:
+				resp.Body.Close()
+				return nil, fs.ErrNotExist
+			case resp.StatusCode >= 500:
+				resp.Body.Close()
+				err = fmt.Errorf("server error: %s", resp.Status)
+				retry = true
+			case resp.StatusCode >= 300:
+				resp.Body.Close()
+				return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+			default:
+				return resp, nil
+			}
+		}
+		if !retry || attempt >= fsys.retries {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+type file struct {
+	ctx  context.Context
+	fsys *FS
+	name string
+	body io.ReadCloser
+	info *fileInfo
+}
+
+var _ io.ReaderAt = (*file)(nil)
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Read(p []byte) (int, error) { return f.body.Read(p) }
+func (f *file) Close() error               { return f.body.Close() }
+
+// ReadAt implements io.ReaderAt with a range request. If the response ends
+// before len(p) bytes are read, the error is io.EOF if the end of the file
+// was reached, and io.ErrUnexpectedEOF otherwise.
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if f.info.size >= 0 && off >= f.info.size {
+		return 0, io.EOF
+	}
+	rng := fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1)
+	resp, err := f.fsys.do(f.ctx, http.MethodGet, f.name, http.Header{"Range": []string{rng}})
+	if err != nil {
+		return 0, &fs.PathError{Op: `read`, Path: f.name, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, &fs.PathError{Op: `read`, Path: f.name, Err: ErrNotSupported}
+	}
+	n, err := io.ReadFull(resp.Body, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) && f.info.size >= 0 && off+int64(n) == f.info.size {
+		// p extends past the end of the file
+		err = io.EOF
+	}
+	return n, err
+}
+
+// isDir returns true if the request for resp was redirected to a directory
+// path
+func isDir(resp *http.Response) bool {
+	return strings.HasSuffix(resp.Request.URL.Path, "/")
+}
+
+// dir is returned by Open for directories
+type dir struct {
+	ctx     context.Context
+	fsys    *FS
+	name    string
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return &fileInfo{name: path.Base(d.name), mode: fs.ModeDir}, nil
+}
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: `read`, Path: d.name, Err: errors.New(`is a directory`)}
+}
+
+func (d *dir) Close() error { return nil }
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fsys.ReadDirContext(d.ctx, d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func newFileInfo(name string, resp *http.Response) *fileInfo {
+	info := &fileInfo{
+		name: path.Base(name),
+		size: resp.ContentLength,
+	}
+	info.modTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() interface{}   { return nil }
+
+type dirEntry struct{ info *fileInfo }
+
+func (e dirEntry) Name() string               { return e.info.name }
+func (e dirEntry) IsDir() bool                { return e.info.IsDir() }
+func (e dirEntry) Type() fs.FileMode          { return e.info.mode.Type() }
+func (e dirEntry) Info() (fs.FileInfo, error) { return e.info, nil }
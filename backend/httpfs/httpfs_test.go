@@ -0,0 +1,270 @@
+package httpfs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/srerickson/ocfl"
+	"github.com/srerickson/ocfl/backend/httpfs"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+var objPath = filepath.Join(`..`, `..`, `test`, `fixtures`, `1.0`, `good-objects`, `spec-ex-full`)
+
+// indexHandler serves files in fsys, with nginx-style JSON indexes for
+// directories if index is true. Like nginx, requests for directories without
+// a trailing slash are redirected.
+func indexHandler(fsys fs.FS, index bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(r.URL.Path, "/")
+		if name == "" {
+			name = "."
+		}
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if info.IsDir() {
+			if !strings.HasSuffix(r.URL.Path, "/") {
+				http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+				return
+			}
+			if !index {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<html></html>"))
+				return
+			}
+			entries, _ := fs.ReadDir(fsys, name)
+			var out []map[string]interface{}
+			for _, e := range entries {
+				typ := "file"
+				if e.IsDir() {
+					typ = "directory"
+				}
+				info, _ := e.Info()
+				out = append(out, map[string]interface{}{
+					"name":  e.Name(),
+					"type":  typ,
+					"size":  info.Size(),
+					"mtime": info.ModTime().UTC().Format(time.RFC1123),
+				})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(out)
+			return
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		defer f.Close()
+		http.ServeContent(w, r, path.Base(name), info.ModTime(), f.(io.ReadSeeker))
+	})
+}
+
+func TestHTTPFS(t *testing.T) {
+	srv := httptest.NewServer(indexHandler(os.DirFS(objPath), true))
+	defer srv.Close()
+	fsys, err := httpfs.New(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// directory listings match the source
+	var expectedPaths, gotPaths []string
+	fs.WalkDir(os.DirFS(objPath), ".", func(name string, d fs.DirEntry, err error) error {
+		expectedPaths = append(expectedPaths, name)
+		return err
+	})
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		gotPaths = append(gotPaths, name)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(gotPaths, ",") != strings.Join(expectedPaths, ",") {
+		t.Errorf("unexpected paths: %v", gotPaths)
+	}
+	info, err := fs.Stat(fsys, `v1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() {
+		t.Error("expected v1 to be a directory")
+	}
+	// 404
+	_, err = fsys.Open(`missing.txt`)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+	// range reads
+	f, err := fsys.Open(`inventory.json`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	expected, err := os.ReadFile(filepath.Join(objPath, `inventory.json`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 10)
+	n, err := f.(io.ReaderAt).ReadAt(buf, 5)
+	if err != nil || n != 10 || string(buf) != string(expected[5:15]) {
+		t.Errorf("unexpected ReadAt result: %d, %v, %q", n, err, buf)
+	}
+	// reading past the end of the file
+	off := int64(len(expected) - 5)
+	n, err = f.(io.ReaderAt).ReadAt(buf, off)
+	if n != 5 || err != io.EOF || string(buf[:n]) != string(expected[off:]) {
+		t.Errorf("unexpected ReadAt result at end of file: %d, %v, %q", n, err, buf[:n])
+	}
+	// read, validate, and logical fs
+	obj, err := ocfl.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := ocfl.ValidateObject(fsys); !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile(logical, `v3/foo/bar.xml`); err != nil {
+		t.Error(err)
+	}
+}
+
+var _ ocfl.OpenContextFS = (*httpfs.FS)(nil)
+
+func TestHTTPFSOpenContext(t *testing.T) {
+	srv := httptest.NewServer(indexHandler(os.DirFS(objPath), true))
+	defer srv.Close()
+	fsys, err := httpfs.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fsys.OpenContext(ctx, `inventory.json`); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, err := ocfl.NewObjectReaderContext(ctx, fsys); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, err := fsys.StatContext(ctx, `inventory.json`); !errors.Is(err, context.Canceled) {
+		t.Errorf("StatContext: expected context.Canceled, got %v", err)
+	}
+	if _, err := fsys.ReadDirContext(ctx, `v1`); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadDirContext: expected context.Canceled, got %v", err)
+	}
+	// directories opened with a context are listed with it
+	root, err := fsys.OpenContext(ctx, `.`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.(fs.ReadDirFile).ReadDir(-1); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadDir: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHTTPFSReadAtTruncated(t *testing.T) {
+	handler := indexHandler(os.DirFS(objPath), true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		// the response ends after 5 of 10 bytes
+		w.Header().Set("Content-Length", "10")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("01234"))
+	}))
+	defer srv.Close()
+	fsys, err := httpfs.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.Open(`inventory.json`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n, err := f.(io.ReaderAt).ReadAt(make([]byte, 10), 0)
+	if n != 5 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected 5 bytes and io.ErrUnexpectedEOF, got %d, %v", n, err)
+	}
+}
+
+func TestHTTPFSNoIndex(t *testing.T) {
+	srv := httptest.NewServer(indexHandler(os.DirFS(objPath), false))
+	defer srv.Close()
+	fsys, err := httpfs.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = fs.ReadDir(fsys, `v1`)
+	if !errors.Is(err, httpfs.ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+	// objects can be opened without directory listings
+	obj, err := ocfl.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.SpecVersion() != `1.0` {
+		t.Errorf("unexpected spec version: %s", obj.SpecVersion())
+	}
+}
+
+func TestHTTPFSRetry(t *testing.T) {
+	var failures int32 = 2
+	handler := indexHandler(os.DirFS(objPath), true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	fsys, err := httpfs.New(srv.URL, httpfs.WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile(fsys, `inventory.json`); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&failures, 3)
+	if _, err := fs.ReadFile(fsys, `inventory.json`); err == nil {
+		t.Fatal("expected error after retries")
+	}
+}
+
+func TestBackendConformance(t *testing.T) {
+	srv := httptest.NewServer(indexHandler(os.DirFS(objPath), true))
+	defer srv.Close()
+	fsys, err := httpfs.New(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps := ocfl.CapabilitiesOf(fsys)
+	if !caps.SupportsRangeReads || !caps.SupportsContext || caps.SupportsRename || caps.SupportsAtomicPut {
+		t.Errorf("unexpected capabilities: %+v", caps)
+	}
+	ocfltest.TestBackend(t, fsys, `inventory.json`, `v1/content/foo/bar.xml`, `v3/inventory.json`)
+}
@@ -13,10 +13,12 @@ import (
 type AliasFS struct {
 	base  fs.FS
 	index *PathTree
+	// alts are alternate targets used if a target doesn't exist
+	alts map[string][]string
 }
 
 func NewAliasFS(fsys fs.FS, files map[string]string) (*AliasFS, error) {
-	afs := &AliasFS{fsys, &PathTree{}}
+	afs := &AliasFS{base: fsys, index: &PathTree{}}
 	for from, to := range files {
 		err := afs.index.Add(from, to)
 		if err != nil {
@@ -29,18 +31,24 @@ func NewAliasFS(fsys fs.FS, files map[string]string) (*AliasFS, error) {
 func (afs *AliasFS) Open(name string) (fs.File, error) {
 	val, err := afs.index.Get(name)
 	if err != nil {
-		return nil, err
+		return nil, aliasPathErr(`open`, name, err)
 	}
 	switch val := val.(type) {
 	case string:
 		// path to a regular file
-		base, err := afs.base.Open(val)
+		var base fs.File
+		target, fallback, err := afs.resolve(val, func(t string) (err error) {
+			base, err = afs.base.Open(t)
+			return
+		})
 		if err != nil {
 			return nil, err
 		}
 		return &aliasFile{
-			File: base,
-			path: name,
+			File:     base,
+			path:     name,
+			target:   target,
+			fallback: fallback,
 		}, nil
 	case *PathTree:
 		// path to a directory
@@ -85,18 +93,24 @@ func (afs *AliasFS) Open(name string) (fs.File, error) {
 func (afs *AliasFS) Stat(name string) (fs.FileInfo, error) {
 	val, err := afs.index.Get(name)
 	if err != nil {
-		return nil, err
+		return nil, aliasPathErr(`stat`, name, err)
 	}
 	switch val := val.(type) {
 	case string:
 		// val is target file
-		info, err := fs.Stat(afs.base, val)
+		var info fs.FileInfo
+		target, fallback, err := afs.resolve(val, func(t string) (err error) {
+			info, err = fs.Stat(afs.base, t)
+			return
+		})
 		if err != nil {
 			return nil, err
 		}
 		return &aliasFileInfo{
-			info,
-			path.Base(name),
+			FileInfo: info,
+			name:     path.Base(name),
+			target:   target,
+			fallback: fallback,
 		}, nil
 	case *PathTree:
 		return &dirFileInfo{
@@ -106,10 +120,41 @@ func (afs *AliasFS) Stat(name string) (fs.FileInfo, error) {
 	return nil, errors.New("unexpected value in AliasFS")
 }
 
+// resolve calls fn with target and then with its alternates until fn
+// returns an error other than fs.ErrNotExist. It returns the last target
+// used, and whether it was an alternate.
+func (afs *AliasFS) resolve(target string, fn func(string) error) (string, bool, error) {
+	err := fn(target)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return target, false, err
+	}
+	for _, alt := range afs.alts[target] {
+		altErr := fn(alt)
+		if altErr == nil {
+			return alt, true, nil
+		}
+		if !errors.Is(altErr, fs.ErrNotExist) {
+			return alt, true, altErr
+		}
+	}
+	return target, false, err
+}
+
+// aliasPathErr returns a *fs.PathError for an error from the index. Missing
+// paths are reported as fs.ErrNotExist.
+func aliasPathErr(op string, name string, err error) error {
+	if errors.Is(err, ErrPathNotFound) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
 type aliasFile struct {
 	fs.File
-	path string
-	info *aliasFileInfo
+	path     string
+	target   string
+	fallback bool
+	info     *aliasFileInfo
 }
 
 func (file *aliasFile) Stat() (fs.FileInfo, error) {
@@ -118,20 +163,39 @@ func (file *aliasFile) Stat() (fs.FileInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		file.info = &aliasFileInfo{base, path.Base(file.path)}
+		file.info = &aliasFileInfo{
+			FileInfo: base,
+			name:     path.Base(file.path),
+			target:   file.target,
+			fallback: file.fallback,
+		}
 	}
 	return file.info, nil
 }
 
 type aliasFileInfo struct {
 	fs.FileInfo
-	name string
+	name     string
+	target   string
+	fallback bool
 }
 
+var _ ContentFileInfo = (*aliasFileInfo)(nil)
+
 func (info *aliasFileInfo) Name() string {
 	return info.name
 }
 
+// ContentPath implements ContentFileInfo
+func (info *aliasFileInfo) ContentPath() string {
+	return info.target
+}
+
+// Fallback implements ContentFileInfo
+func (info *aliasFileInfo) Fallback() bool {
+	return info.fallback
+}
+
 type dirFileInfo struct {
 	name string
 	// mode    fs.FileMode
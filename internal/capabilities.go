@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"io/fs"
+)
+
+// Capabilities describes optional features of a storage backend.
+type Capabilities struct {
+	// SupportsRename is true if files can be renamed without copying.
+	SupportsRename bool
+	// SupportsKeyListing is true if all files under a prefix can be listed
+	// without walking directories.
+	SupportsKeyListing bool
+	// SupportsRangeReads is true if files returned by Open implement
+	// io.ReaderAt.
+	SupportsRangeReads bool
+	// SupportsAtomicPut is true if written files become visible all at
+	// once.
+	SupportsAtomicPut bool
+	// SupportsContext is true if the backend implements OpenContextFS.
+	SupportsContext bool
+	// MaxKeyLength is the maximum length of a file path, or 0 if there is
+	// no limit or it is unknown.
+	MaxKeyLength int
+}
+
+// CapabilitiesFS is implemented by backends that declare their
+// Capabilities.
+type CapabilitiesFS interface {
+	fs.FS
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the Capabilities of fsys. If fsys implements
+// CapabilitiesFS, its declared capabilities are returned. Otherwise,
+// capabilities are inferred from the interfaces fsys implements, and
+// features that can't be inferred are reported as unsupported.
+func CapabilitiesOf(fsys fs.FS) Capabilities {
+	if capFS, ok := fsys.(CapabilitiesFS); ok {
+		return capFS.Capabilities()
+	}
+	var caps Capabilities
+	_, caps.SupportsContext = fsys.(OpenContextFS)
+	return caps
+}
@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MetricFilesResumed counts content files that weren't digested because
+// they were verified in an earlier, interrupted validation (label: alg).
+const MetricFilesResumed = `ocfl_files_resumed_total`
+
+// Checkpoint records content files digested during validation, so an
+// interrupted validation can be resumed. Digests are recorded whether or not
+// they match the manifest: errors for recorded files are reported when the
+// validation is resumed.
+type Checkpoint struct {
+	// InventoryDigest is the root inventory digest when the checkpoint was
+	// created. Checkpoints for other inventories are ignored.
+	InventoryDigest string `json:"inventory_digest"`
+	// Verified maps content paths to their digests
+	Verified map[string]string `json:"verified"`
+}
+
+// Checkpointer persists a Checkpoint between validations. Implementations
+// are only used by one validation at a time.
+type Checkpointer interface {
+	// Load returns the saved Checkpoint, or nil if there isn't one.
+	Load() (*Checkpoint, error)
+	// Save saves the checkpoint, replacing any saved checkpoint.
+	Save(*Checkpoint) error
+	// Clear removes the saved checkpoint. It is called when content
+	// validation completes.
+	Clear() error
+}
+
+// WithCheckpoint configures validation to record digested content files
+// with cp and to skip files recorded by an earlier validation of the same
+// root inventory. Files with fixity values are digested again, so their
+// fixity can be checked. The checkpoint is saved periodically (see
+// WithCheckpointInterval) and when validation is interrupted.
+func WithCheckpoint(cp Checkpointer) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.checkpoint = cp
+	}
+}
+
+// WithCheckpointInterval sets how often the checkpoint set with
+// WithCheckpoint is saved: after files digests or after d has elapsed since
+// it was last saved. The default is 1000 files or 30 seconds.
+func WithCheckpointInterval(files int, d time.Duration) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.checkpointFiles = files
+		opts.checkpointInterval = d
+	}
+}
+
+// FileCheckpointer is a Checkpointer that saves checkpoints as JSON in a
+// local file.
+type FileCheckpointer struct {
+	Name string // file name
+}
+
+var _ Checkpointer = (*FileCheckpointer)(nil)
+
+// Load implements Checkpointer for FileCheckpointer
+func (f *FileCheckpointer) Load() (*Checkpoint, error) {
+	data, err := os.ReadFile(f.Name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Save implements Checkpointer for FileCheckpointer. The checkpoint is
+// written to a temporary file that replaces the named file.
+func (f *FileCheckpointer) Save(cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Name), filepath.Base(f.Name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Name)
+}
+
+// Clear implements Checkpointer for FileCheckpointer
+func (f *FileCheckpointer) Clear() error {
+	err := os.Remove(f.Name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// checkpointContent is like digestContent, but content files recorded in
+// the checkpoint aren't digested, unless they have values in fixity, and
+// digested files are recorded in it.
+func (obj *ObjectReader) checkpointContent(fixity fixityMap) (DigestMap, fixityMap, error) {
+	cpr := obj.opts.checkpoint
+	alg := obj.inventory.DigestAlgorithm
+	algs, err := obj.contentAlgs(fixity)
+	if err != nil {
+		return nil, nil, err
+	}
+	invDigest := hex.EncodeToString(obj.inventory.digest)
+	cp, err := cpr.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cp == nil || cp.InventoryDigest != invDigest {
+		cp = &Checkpoint{InventoryDigest: invDigest}
+	}
+	if cp.Verified == nil {
+		cp.Verified = map[string]string{}
+	}
+	var content PathIndex
+	var todo []string
+	var resumed int
+	sizes := map[string]int64{}
+	err = obj.walkContent(func(name string, d fs.DirEntry) error {
+		if digest, ok := cp.Verified[name]; ok && !fixity.has(name) {
+			resumed++
+			return content.Add(digest, name)
+		}
+		if obj.opts.metrics != nil || obj.opts.largestFirst {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			sizes[name] = info.Size()
+		}
+		todo = append(todo, name)
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj.opts.largestFirst {
+		sortLargestFirst(todo, sizes)
+	}
+	obj.opts.getMetrics().Add(MetricFilesResumed, map[string]string{"alg": alg}, float64(resumed))
+	every, interval := obj.opts.checkpointFiles, obj.opts.checkpointInterval
+	if every <= 0 {
+		every = 1000
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	var pending int
+	saved := time.Now()
+	digests := fixity.empty()
+	each := func(name string, sums map[string]string, err error) error {
+		if err != nil {
+			return err
+		}
+		obj.recordDigests(sums, sizes[name])
+		digests.add(name, sums)
+		digest := sums[alg]
+		cp.Verified[name] = digest
+		pending++
+		if pending >= every || time.Since(saved) >= interval {
+			if err := cpr.Save(cp); err != nil {
+				return err
+			}
+			pending, saved = 0, time.Now()
+		}
+		return content.Add(digest, name)
+	}
+	if err := digestPathsAlgs(obj.root.context(), obj.root, algs, todo, each); err != nil {
+		// save progress so validation can be resumed
+		if saveErr := cpr.Save(cp); saveErr != nil {
+			return nil, nil, saveErr
+		}
+		return nil, nil, err
+	}
+	if err := cpr.Clear(); err != nil {
+		return nil, nil, err
+	}
+	return content.DigestMap(), digests, nil
+}
@@ -0,0 +1,142 @@
+package internal_test
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+// cancelFS calls cancel after n content files (.dat) are read and closed
+type cancelFS struct {
+	fs.FS
+	n      int32
+	closed int32
+	cancel context.CancelFunc
+}
+
+func (fsys *cancelFS) Open(name string) (fs.File, error) {
+	f, err := fsys.FS.Open(name)
+	if err != nil || !strings.HasSuffix(name, ".dat") {
+		return f, err
+	}
+	return &cancelFile{File: f, fsys: fsys}, nil
+}
+
+type cancelFile struct {
+	fs.File
+	fsys *cancelFS
+}
+
+func (f *cancelFile) Close() error {
+	if atomic.AddInt32(&f.fsys.closed, 1) == f.fsys.n {
+		f.fsys.cancel()
+	}
+	return f.File.Close()
+}
+
+func TestValidateCheckpoint(t *testing.T) {
+	obj := ocfltest.NewObject(t, ocfltest.WithVersions(5), ocfltest.WithFiles(8))
+	const total = 40
+	cp := &internal.FileCheckpointer{Name: filepath.Join(t.TempDir(), "checkpoint.json")}
+	digested := `ocfl_files_digested_total{alg=sha512}`
+	resumed := `ocfl_files_resumed_total{alg=sha512}`
+
+	// interrupted validation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fsys := &cancelFS{FS: obj.FS, n: 10, cancel: cancel}
+	m1 := &testMetrics{}
+	result := internal.ValidateObjectContext(ctx, fsys,
+		internal.WithCheckpoint(cp),
+		internal.WithCheckpointInterval(5, 0),
+		internal.WithMetrics(m1))
+	if result.Valid() {
+		t.Fatal("expected interrupted validation to fail")
+	}
+	saved, err := cp.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved == nil || len(saved.Verified) != int(m1.counters[digested]) {
+		t.Fatalf("checkpoint doesn't match files digested: %v", m1.counters)
+	}
+	if len(saved.Verified) == 0 || len(saved.Verified) >= total {
+		t.Fatalf("expected a partial checkpoint, got %d files", len(saved.Verified))
+	}
+
+	// resumed validation
+	m2 := &testMetrics{}
+	result = internal.ValidateObject(obj.FS, internal.WithCheckpoint(cp), internal.WithMetrics(m2))
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	if m2.counters[resumed] != m1.counters[digested] {
+		t.Errorf("expected %v files resumed, got %v", m1.counters[digested], m2.counters[resumed])
+	}
+	if n := m1.counters[digested] + m2.counters[digested]; n != total {
+		t.Errorf("expected %d files digested in both validations, got %v", total, n)
+	}
+	if saved, err := cp.Load(); err != nil || saved != nil {
+		t.Errorf("expected checkpoint to be cleared: %v, %v", saved, err)
+	}
+
+	// checkpoints for a different inventory are ignored
+	if err := cp.Save(&internal.Checkpoint{
+		InventoryDigest: "abc",
+		Verified:        map[string]string{"v1/content/dir-0/file-1-0.dat": "abc"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	m3 := &testMetrics{}
+	result = internal.ValidateObject(obj.FS, internal.WithCheckpoint(cp), internal.WithMetrics(m3))
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	if m3.counters[digested] != total || m3.counters[resumed] != 0 {
+		t.Errorf("expected stale checkpoint to be ignored: %v", m3.counters)
+	}
+}
+
+// files with fixity values are digested again, even if they were verified in
+// a checkpoint
+func TestValidateCheckpointFixity(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, invDigest, err := obj.InventoryDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := obj.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verified := map[string]string{}
+	for digest, names := range content {
+		for _, name := range names {
+			verified[name] = digest
+		}
+	}
+	cp := &internal.FileCheckpointer{Name: filepath.Join(t.TempDir(), "checkpoint.json")}
+	if err := cp.Save(&internal.Checkpoint{InventoryDigest: invDigest, Verified: verified}); err != nil {
+		t.Fatal(err)
+	}
+	m := &testMetrics{}
+	result := internal.ValidateObject(fsys, internal.WithCheckpoint(cp), internal.WithMetrics(m))
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	digested := m.counters[`ocfl_files_digested_total{alg=sha512}`]
+	resumed := m.counters[`ocfl_files_resumed_total{alg=sha512}`]
+	if digested != 4 || resumed != 0 {
+		t.Errorf("expected 4 files digested and none resumed, got %v", m.counters)
+	}
+}
@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -24,6 +25,8 @@ import (
 	"hash"
 	"io/fs"
 	"runtime"
+	"sort"
+	"sync"
 
 	"github.com/srerickson/checksum"
 	"golang.org/x/crypto/blake2b"
@@ -61,48 +64,159 @@ func newHash(alg string) (func() hash.Hash, error) {
 	case MD5:
 		return md5.New, nil
 	case BLAKE2B:
-		h, err := blake2b.New512(nil)
-		if err != nil {
+		if _, err := blake2b.New512(nil); err != nil {
 			return nil, err
 		}
+		// each call must return a new hash: they are used concurrently
 		return func() hash.Hash {
+			h, _ := blake2b.New512(nil)
 			return h
 		}, nil
 	}
-	return nil, fmt.Errorf(`unknown checksum algorithm: %s`, alg)
+	registeredAlgs.RLock()
+	defer registeredAlgs.RUnlock()
+	if newH, ok := registeredAlgs.m[alg]; ok {
+		return newH, nil
+	}
+	return nil, fmt.Errorf(`%w: %s`, ErrUnsupportedAlgorithm, alg)
+}
+
+// ErrUnsupportedAlgorithm indicates a digest algorithm that isn't built in
+// or registered with RegisterDigestAlgorithm.
+var ErrUnsupportedAlgorithm = errors.New(`unsupported digest algorithm`)
+
+var registeredAlgs = struct {
+	sync.RWMutex
+	m map[string]func() hash.Hash
+}{
+	m: map[string]func() hash.Hash{},
 }
 
-// NumDigesters sets concurrency for Digest
+// RegisterDigestAlgorithm registers newH as the implementation of digest
+// algorithm name, so fixity values using it can be verified. newH must
+// return a new hash each time it is called. Built-in algorithms can't be
+// replaced.
+func RegisterDigestAlgorithm(name string, newH func() hash.Hash) {
+	registeredAlgs.Lock()
+	defer registeredAlgs.Unlock()
+	registeredAlgs.m[name] = newH
+}
+
+// NumDigesters sets the number of files digested concurrently
 var NumDigesters = runtime.GOMAXPROCS(0)
 
+// WithLargestFirst configures content digesting to start with the largest
+// files, so a few large files aren't left to be digested after other
+// digesters have finished. It only applies where file sizes are found by
+// listing content directories before digesting begins: Validate, Content,
+// and ScanContent. Otherwise, files are digested in the order they are
+// found.
+func WithLargestFirst() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.largestFirst = true
+	}
+}
+
+// sortLargestFirst sorts paths by descending size in sizes. Paths with the
+// same size keep their order.
+func sortLargestFirst(paths []string, sizes map[string]int64) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return sizes[paths[i]] > sizes[paths[j]]
+	})
+}
+
 // ContentMap concurrently calculates checksum of every file in dir
 // using Hash algorithm alg, returning results as a ContentMap
 func ContentMap(fsys fs.FS, root string, alg string) (DigestMap, error) {
-	var cm DigestMap
-	newH, err := newHash(alg)
+	var idx PathIndex
+	each := func(name string, digest string, _ int64) error {
+		return idx.Add(digest, name)
+	}
+	err := walkDigests(context.Background(), fsys, root, alg, false, nil, each)
 	if err != nil {
 		return nil, err
 	}
-	each := func(j checksum.Job, err error) error {
+	return idx.DigestMap(), nil
+}
+
+// walkDigests concurrently digests every regular file in root with alg,
+// calling each with the file's path, digest, and size. Sizes are only
+// recorded if sizes is not nil; otherwise they are 0. If root doesn't exist,
+// walkDigests returns nil if allowMissing is true, or an error wrapping
+// fs.ErrNotExist. Errors from the checksum package are unwrapped so they
+// can be checked with errors.Is.
+func walkDigests(ctx context.Context, fsys fs.FS, root string, alg string, allowMissing bool, sizes *sizeIndex, each func(string, string, int64) error) error {
+	newH, err := newHash(alg)
+	if err != nil {
+		return err
+	}
+	algs := map[string]func() hash.Hash{alg: newH}
+	return walkDigestsAlgs(ctx, fsys, root, algs, allowMissing, sizes, func(name string, sums map[string]string, size int64) error {
+		return each(name, sums[alg], size)
+	})
+}
+
+// walkDigestsAlgs is like walkDigests, but each file is digested with every
+// algorithm in algs as it is read. each is called with the file's digests by
+// algorithm.
+func walkDigestsAlgs(ctx context.Context, fsys fs.FS, root string, algs map[string]func() hash.Hash, allowMissing bool, sizes *sizeIndex, each func(string, map[string]string, int64) error) error {
+	jobFunc := func(j checksum.Job, err error) error {
 		if err != nil {
 			return err
 		}
-		sum, err := j.SumString(alg)
-		// fmt.Println(j.Path())
+		sums, err := jobSums(j, algs)
 		if err != nil {
 			return err
 		}
-		return cm.Add(sum, j.Path())
+		var size int64
+		if sizes != nil {
+			size = sizes.pop(j.Path())
+		}
+		return each(j.Path(), sums, size)
 	}
-	err = checksum.Walk(fsys, root, each, checksum.WithAlg(alg, newH))
-	if err != nil {
-		walkErr, _ := err.(*checksum.WalkErr)
-		// if the walk failed because the dir doesn't exists
-		// return that error
-		if errors.Is(walkErr.WalkDirErr, fs.ErrNotExist) {
-			return nil, walkErr.WalkDirErr
+	opts := append(checksumAlgs(algs),
+		checksum.WithCtx(ctx),
+		checksum.WithGos(NumDigesters),
+	)
+	if sizes != nil {
+		opts = append(opts, checksum.WithWalkDirFunc(sizes.walkDirFunc))
+	}
+	err := checksum.Walk(fsys, root, jobFunc, opts...)
+	if err == nil {
+		return nil
+	}
+	walkErr, ok := err.(*checksum.WalkErr)
+	if !ok {
+		return err
+	}
+	// WalkErr doesn't implement Unwrap
+	if walkErr.JobFuncErr != nil {
+		return walkErr.JobFuncErr
+	}
+	if allowMissing && errors.Is(walkErr.WalkDirErr, fs.ErrNotExist) {
+		return nil
+	}
+	return walkErr.WalkDirErr
+}
+
+// checksumAlgs returns checksum options for digesting with each of algs
+func checksumAlgs(algs map[string]func() hash.Hash) []func(*checksum.Config) {
+	opts := make([]func(*checksum.Config), 0, len(algs))
+	for alg, newH := range algs {
+		opts = append(opts, checksum.WithAlg(alg, newH))
+	}
+	return opts
+}
+
+// jobSums returns the job's hex-encoded digests for each of algs
+func jobSums(j checksum.Job, algs map[string]func() hash.Hash) (map[string]string, error) {
+	sums := make(map[string]string, len(algs))
+	for alg := range algs {
+		sum, err := j.SumString(alg)
+		if err != nil {
+			return nil, err
 		}
-		return nil, err
+		sums[alg] = sum
 	}
-	return cm, nil
+	return sums, nil
 }
@@ -1,11 +1,14 @@
 package internal_test
 
 import (
+	"encoding/hex"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/srerickson/ocfl/internal"
+	"golang.org/x/crypto/blake2b"
 )
 
 func TestNewContentMap(t *testing.T) {
@@ -15,3 +18,27 @@ func TestNewContentMap(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestContentMapBlake2b(t *testing.T) {
+	// blake2b hashes must not be shared between concurrent digesters
+	dir := filepath.Join(goodObjPath, `spec-ex-full`)
+	fsys := os.DirFS(dir)
+	cm, err := internal.ContentMap(fsys, `.`, internal.BLAKE2B)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := cm.Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, digest := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := blake2b.Sum512(data)
+		if expected := hex.EncodeToString(sum[:]); digest != expected {
+			t.Errorf("wrong digest for %s", p)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package internal_test
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// TestConcurrentReads should be run with -race
+func TestConcurrentReads(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, err := obj.InventoryAt(`v3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			f, err := logical.Open(`v3/foo/bar.xml`)
+			if err != nil {
+				errs <- err
+				return
+			}
+			_, err = io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, err := fs.Stat(logical, `v1/image.tiff`); err != nil {
+				errs <- err
+				return
+			}
+			if inv.Inventory.Manifest.GetDigest(`v1/content/image.tiff`) == "" {
+				errs <- fs.ErrNotExist
+				return
+			}
+			if _, err := obj.ListFiles(`v2`, internal.WithListPage(1, "")); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := obj.ResolvedPath(`v2/empty2.txt`); err != nil {
+				errs <- err
+				return
+			}
+			if i%10 == 0 {
+				if result := obj.ValidateContext(context.Background()); !result.Valid() {
+					errs <- result.Fatal()[0]
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"sort"
+)
+
+// LibraryVersion is the version of this library.
+const LibraryVersion = "0.0.0"
+
+// ConformanceInfo describes the OCFL features supported by this library. It
+// is returned by Conformance and included in ValidationResults.
+type ConformanceInfo struct {
+	LibraryVersion   string   `json:"library_version"`
+	SpecVersions     []string `json:"spec_versions"`
+	DigestAlgorithms []string `json:"digest_algorithms"`
+	Extensions       []string `json:"extensions"`
+}
+
+// Conformance returns a snapshot of the OCFL spec versions, digest
+// algorithms, and extensions currently supported.
+func Conformance() ConformanceInfo {
+	return ConformanceInfo{
+		LibraryVersion:   LibraryVersion,
+		SpecVersions:     SupportedSpecVersions(),
+		DigestAlgorithms: SupportedDigestAlgorithms(),
+		Extensions:       SupportedExtensions(),
+	}
+}
+
+// SupportedSpecVersions returns the OCFL spec versions implemented by this
+// library.
+func SupportedSpecVersions() []string {
+	return []string{ocflVersion}
+}
+
+// SupportedDigestAlgorithms returns the names of built-in digest algorithms
+// and algorithms registered with RegisterDigestAlgorithm, sorted by name.
+func SupportedDigestAlgorithms() []string {
+	var algs []string
+	for _, alg := range digestAlgorithms {
+		if _, err := newHash(alg); err == nil {
+			algs = append(algs, alg)
+		}
+	}
+	registeredAlgs.RLock()
+	for alg := range registeredAlgs.m {
+		if !stringIn(alg, algs) {
+			algs = append(algs, alg)
+		}
+	}
+	registeredAlgs.RUnlock()
+	sort.Strings(algs)
+	return algs
+}
+
+// SupportedExtensions returns the names of extensions with registered
+// validators (see RegisterExtensionValidator), sorted by name.
+func SupportedExtensions() []string {
+	extensionValidators.RLock()
+	defer extensionValidators.RUnlock()
+	exts := make([]string, 0, len(extensionValidators.m))
+	for name := range extensionValidators.m {
+		exts = append(exts, name)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+func stringIn(s string, vals []string) bool {
+	for _, v := range vals {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
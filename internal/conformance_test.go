@@ -0,0 +1,78 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestConformance(t *testing.T) {
+	conf := internal.Conformance()
+	if conf.LibraryVersion != internal.LibraryVersion {
+		t.Errorf("unexpected library version: %s", conf.LibraryVersion)
+	}
+	if !reflect.DeepEqual(conf.SpecVersions, []string{"1.0"}) {
+		t.Errorf("unexpected spec versions: %v", conf.SpecVersions)
+	}
+	for _, alg := range []string{internal.SHA512, internal.SHA256, internal.BLAKE2B} {
+		if !contains(conf.DigestAlgorithms, alg) {
+			t.Errorf("expected %s in digest algorithms: %v", alg, conf.DigestAlgorithms)
+		}
+	}
+	if contains(conf.DigestAlgorithms, internal.SHA224) {
+		t.Error("sha224 isn't implemented")
+	}
+	if !contains(conf.Extensions, internal.MutableHeadExtension) {
+		t.Errorf("expected %s in extensions: %v", internal.MutableHeadExtension, conf.Extensions)
+	}
+	// registered extensions are included
+	ext := fmt.Sprintf("example-conformance-%d", time.Now().UnixNano())
+	internal.RegisterExtensionValidator(ext, func(fs.FS, string, *internal.Inventory) []error { return nil })
+	if !contains(internal.Conformance().Extensions, ext) {
+		t.Errorf("expected %s in extensions after registering", ext)
+	}
+	// earlier snapshot is unchanged
+	if contains(conf.Extensions, ext) {
+		t.Error("snapshot changed after registering extension")
+	}
+	// JSON
+	data, err := json.Marshal(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded internal.ConformanceInfo
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, conf) {
+		t.Errorf("JSON round trip: got %v, expected %v", decoded, conf)
+	}
+}
+
+func TestValidationConformance(t *testing.T) {
+	result := internal.ValidateObject(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if !reflect.DeepEqual(result.Conformance(), internal.Conformance()) {
+		t.Errorf("unexpected conformance in result: %v", result.Conformance())
+	}
+	// also for objects that can't be opened
+	result = internal.ValidateObject(os.DirFS(filepath.Join(badObjPath, `E003_no_decl`)))
+	if result.Conformance().LibraryVersion != internal.LibraryVersion {
+		t.Errorf("unexpected conformance in result: %v", result.Conformance())
+	}
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
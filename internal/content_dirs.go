@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// ErrContentDirIntroduced is the warning code for a contentDirectory first
+// set after the first version. E019 requires it to be set in the first
+// version, but these objects are still readable using each version's content
+// directory, so they are warned about instead of rejected. Add the code to
+// a Profile's Promote list to reject them.
+var ErrContentDirIntroduced = OCFLCodeErr{
+	Description: "The contentDirectory should be set in the first version of the object.",
+	Code:        "CONTENT-DIR-INTRODUCED",
+}
+
+// contentDirDecl is the contentDirectory declared by a version inventory
+type contentDirDecl struct {
+	dir string // content directory name
+	set bool   // the contentDirectory key was present
+}
+
+// versionContentDir returns the content directory name for version vname:
+// the content directory of the version's own inventory if it differs from
+// the inventory's (see resolveContentDirs), or the inventory's.
+func (inv *Inventory) versionContentDir(vname string) string {
+	if dir, ok := inv.versionContentDirs[vname]; ok {
+		return dir
+	}
+	return inv.contentDirectory()
+}
+
+// resolveContentDirs sets the content directories of versions with manifest
+// paths outside the inventory's content directory, using the version's own
+// inventory. Versions without a readable inventory use the inventory's
+// content directory. Other version inventories aren't read.
+func (root *objectRoot) resolveContentDirs(inv *Inventory) {
+	inv.versionContentDirs = nil
+	others := map[string]bool{}
+	for _, paths := range inv.Manifest {
+		for _, p := range paths {
+			ref, err := ParseContentRef(p)
+			if err != nil || ref.ContentDir == inv.contentDirectory() {
+				continue
+			}
+			if _, ok := inv.Versions[ref.Version]; ok {
+				others[ref.Version] = true
+			}
+		}
+	}
+	for v := range others {
+		vinv, err := root.readInventory(v, false)
+		if err != nil || vinv.contentDirectory() == inv.contentDirectory() {
+			continue
+		}
+		if inv.versionContentDirs == nil {
+			inv.versionContentDirs = map[string]string{}
+		}
+		inv.versionContentDirs[v] = vinv.contentDirectory()
+	}
+}
+
+// validateContentDirs checks that the contentDirectory declared by version
+// inventories (decls, by version name) doesn't change once it is set (E019).
+// If it is first set after the first version, a warning is added to result.
+func (obj *ObjectReader) validateContentDirs(decls map[string]contentDirDecl, result *validationResult) {
+	if _, ok := decls[obj.inventory.Head]; !ok {
+		decls[obj.inventory.Head] = contentDirDecl{
+			dir: obj.inventory.contentDirectory(),
+			set: !obj.inventory.noContentDir,
+		}
+	}
+	vnames := make([]string, 0, len(decls))
+	for v := range decls {
+		vnames = append(vnames, v)
+	}
+	sortVersions(vnames)
+	var setIn string // version that first set contentDirectory
+	var unset string // last version without contentDirectory before setIn
+	for _, v := range vnames {
+		decl := decls[v]
+		if setIn == "" {
+			if !decl.set {
+				unset = v
+				continue
+			}
+			setIn = v
+			if unset != "" && decl.dir != contentDir {
+				err := fmt.Errorf("contentDirectory is set to %q in %s, but not in %s", decl.dir, v, unset)
+				result.AddWarn(err, &ErrContentDirIntroduced)
+			}
+			continue
+		}
+		if setDir := decls[setIn].dir; decl.dir != setDir {
+			err := fmt.Errorf("contentDirectory changed from %q in %s to %q in %s", setDir, setIn, decl.dir, v)
+			result.AddFatal(err, &ErrE019)
+		}
+	}
+}
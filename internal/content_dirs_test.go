@@ -0,0 +1,73 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestContentDirSetLater(t *testing.T) {
+	// v1 and v2 use the default content directory; v3 and v4 use "data"
+	fsys := os.DirFS(filepath.Join(warnObjPath, `content_dir_set_in_v3`))
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	if len(result.Warning()) != 1 || !errors.Is(result.Warning()[0], &internal.ErrContentDirIntroduced) {
+		t.Errorf("expected a %s warning, got %v", internal.ErrContentDirIntroduced.Code, result.Warning())
+	}
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := obj.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := content.Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{
+		"v1/content/a_file.txt",
+		"v2/content/b_file.txt",
+		"v3/data/a_file.txt",
+		"v4/data/c_file.txt",
+	} {
+		if _, ok := paths[p]; !ok {
+			t.Errorf("content is missing %s", p)
+		}
+	}
+	if len(paths) != 4 {
+		t.Errorf("expected 4 content paths, got %v", paths)
+	}
+	unreferenced, err := obj.UnreferencedContent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unreferenced) > 0 {
+		t.Errorf("unexpected unreferenced content: %v", unreferenced)
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := fs.ReadFile(logical, "v4/b_file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "New b_file\n" {
+		t.Errorf("unexpected content for v4/b_file.txt: %q", data)
+	}
+	// promoted to an error
+	profile := &internal.Profile{Promote: []string{internal.ErrContentDirIntroduced.Code}}
+	result = internal.ValidateObject(fsys, internal.WithProfile(profile))
+	if result.Valid() {
+		t.Error("expected validation to fail with promoted warning")
+	}
+}
@@ -0,0 +1,109 @@
+package internal_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+// opsFS counts calls to Open and Stat by path. Directories are listed by
+// opening them.
+type opsFS struct {
+	fs.FS
+	mx    sync.Mutex
+	opens map[string]int
+	stats map[string]int
+}
+
+func (fsys *opsFS) Open(name string) (fs.File, error) {
+	fsys.mx.Lock()
+	fsys.opens[name]++
+	fsys.mx.Unlock()
+	return fsys.FS.Open(name)
+}
+
+func (fsys *opsFS) Stat(name string) (fs.FileInfo, error) {
+	fsys.mx.Lock()
+	fsys.stats[name]++
+	fsys.mx.Unlock()
+	return fs.Stat(fsys.FS, name)
+}
+
+// Content files should be opened once during validation, and never stated,
+// even with metrics (which record sizes).
+func TestValidateContentOps(t *testing.T) {
+	obj := ocfltest.NewObject(t, ocfltest.WithVersions(3), ocfltest.WithFiles(5))
+	fsys := &opsFS{FS: obj.FS, opens: map[string]int{}, stats: map[string]int{}}
+	result := internal.ValidateObject(fsys, internal.WithMetrics(&testMetrics{}))
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	var contentFiles int
+	for name := range obj.FS {
+		if !strings.Contains(name, "/content/") {
+			continue
+		}
+		contentFiles++
+		if n := fsys.opens[name]; n != 1 {
+			t.Errorf("%s opened %d times", name, n)
+		}
+		if n := fsys.stats[name]; n != 0 {
+			t.Errorf("%s stated %d times", name, n)
+		}
+	}
+	if contentFiles != 15 {
+		t.Fatalf("expected 15 content files, got %d", contentFiles)
+	}
+}
+
+// Fixity values are checked with the digests from the content pass, so
+// content files are still opened once. Content directories are listed once
+// to validate the version directory, and once to digest their files.
+func TestValidateContentOpsFixity(t *testing.T) {
+	expected := map[string]int{
+		`v1/content`:             2,
+		`v1/content/empty.txt`:   1,
+		`v1/content/foo`:         1,
+		`v1/content/foo/bar.xml`: 1,
+		`v1/content/image.tiff`:  1,
+		`v2/content`:             2,
+		`v2/content/foo`:         1,
+		`v2/content/foo/bar.xml`: 1,
+		`v3/content`:             1,
+	}
+	for name, opts := range map[string][]internal.ObjectOption{
+		`default`:       {internal.WithMetrics(&testMetrics{})},
+		`largest-first`: {internal.WithMetrics(&testMetrics{}), internal.WithLargestFirst()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fsys := &opsFS{
+				FS:    os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)),
+				opens: map[string]int{},
+				stats: map[string]int{},
+			}
+			result := internal.ValidateObject(fsys, opts...)
+			if !result.Valid() {
+				t.Fatal(result.Fatal())
+			}
+			opens := map[string]int{}
+			for name, n := range fsys.opens {
+				if strings.Contains(name, "/content") {
+					opens[name] = n
+				}
+			}
+			if !reflect.DeepEqual(opens, expected) {
+				t.Errorf("expected content opens %v, got %v", expected, opens)
+			}
+			if len(fsys.stats) != 0 {
+				t.Errorf("expected no stats, got %v", fsys.stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ContentFileInfo is implemented by the fs.FileInfo of files in an
+// ObjectReader's LogicalFS. It identifies the content file that was read.
+type ContentFileInfo interface {
+	fs.FileInfo
+	// ContentPath returns the content path, relative to the object root, of
+	// the file that was opened.
+	ContentPath() string
+	// Fallback returns true if the preferred content path for the file's
+	// digest was missing and another manifest path was used.
+	Fallback() bool
+}
+
+// contentCandidates returns the manifest paths for digest in order of
+// preference: content paths from earlier versions are preferred, and paths
+// from the same version are sorted lexically.
+func (inv *Inventory) contentCandidates(digest string) []string {
+	paths := append([]string{}, inv.Manifest[digest]...)
+	num := func(p string) int {
+		n, _, err := versionParse(strings.SplitN(p, "/", 2)[0])
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		ni, nj := num(paths[i]), num(paths[j])
+		if ni != nj {
+			return ni < nj
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+// ResolvedPath returns the content path that is read for logical, a path in
+// the ObjectReader's LogicalFS (e.g., "v1/file.txt"). The manifest path from
+// the earliest version is used, unless it doesn't exist, in which case the
+// next available manifest path is used.
+func (obj *ObjectReader) ResolvedPath(logical string) (string, error) {
+	parts := strings.SplitN(logical, "/", 2)
+	if _, ok := obj.inventory.Versions[parts[0]]; !ok || len(parts) < 2 {
+		return "", &fs.PathError{Op: `resolve`, Path: logical, Err: fs.ErrNotExist}
+	}
+	state, err := obj.inventory.stateIndex(parts[0])
+	if err != nil {
+		return "", err
+	}
+	digest, ok := state.PathDigest(parts[1])
+	if !ok {
+		return "", &fs.PathError{Op: `resolve`, Path: logical, Err: fs.ErrNotExist}
+	}
+	candidates := obj.inventory.contentCandidates(digest)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("empty path list for digest: %s", digest)
+	}
+	for _, p := range candidates {
+		_, err := fs.Stat(obj.root, p)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", &fs.PathError{Op: `resolve`, Path: path.Clean(logical), Err: fs.ErrNotExist}
+}
@@ -0,0 +1,91 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestLogicalFSFallback(t *testing.T) {
+	const barDigest = `7dcc352f96c56dc5b094b2492c2866afeb12136a78f0143431ae247d02f02497bbd733e0536d34ec9703eba14c6017ea9f5738322c1d43169f8c77785947ac31`
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	// add a second copy of v1/content/foo/bar.xml in v3, listed first
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	manifest := inv["manifest"].(map[string]interface{})
+	manifest[barDigest] = []string{`v3/content/copy.xml`, `v1/content/foo/bar.xml`}
+	setInventory(t, fsys, `.`, inv)
+	fsys[`v3/content/copy.xml`] = fsys[`v1/content/foo/bar.xml`]
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the earliest version's copy is preferred
+	resolved, err := obj.ResolvedPath(`v1/foo/bar.xml`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != `v1/content/foo/bar.xml` {
+		t.Errorf("expected v1 content path, got %s", resolved)
+	}
+	// remove the preferred copy
+	delete(fsys, `v1/content/foo/bar.xml`)
+	resolved, err = obj.ResolvedPath(`v1/foo/bar.xml`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != `v3/content/copy.xml` {
+		t.Errorf("expected fallback content path, got %s", resolved)
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{`v1/foo/bar.xml`, `v3/image.tiff`} {
+		f, err := logical.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := f.Stat()
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cinfo, ok := info.(internal.ContentFileInfo)
+		if !ok {
+			t.Fatalf("%s: expected ContentFileInfo, got %T", name, info)
+		}
+		fallback := name == `v1/foo/bar.xml`
+		if cinfo.Fallback() != fallback {
+			t.Errorf("%s: expected Fallback() to be %v", name, fallback)
+		}
+		resolved, err := obj.ResolvedPath(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cinfo.ContentPath() != resolved {
+			t.Errorf("%s: ContentPath() is %s, ResolvedPath is %s", name, cinfo.ContentPath(), resolved)
+		}
+	}
+	info, err := fs.Stat(logical, `v1/foo/bar.xml`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.(internal.ContentFileInfo).ContentPath() != `v3/content/copy.xml` {
+		t.Errorf("unexpected content path from Stat: %s", info.(internal.ContentFileInfo).ContentPath())
+	}
+	// no copies left
+	delete(fsys, `v3/content/copy.xml`)
+	if _, err := logical.Open(`v1/foo/bar.xml`); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+	if _, err := obj.ResolvedPath(`v1/missing.txt`); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContentRef is a content path in the manifest: a file in a version's
+// content directory. Content paths are always slash-separated.
+type ContentRef struct {
+	Version    string // version directory, e.g. v1
+	ContentDir string // content directory name, e.g. content
+	RelPath    string // path relative to the content directory
+}
+
+// String returns the content path, in the form
+// `{version}/{contentDirectory}/{path}`.
+func (ref ContentRef) String() string {
+	if ref.RelPath == "" {
+		return ref.Dir()
+	}
+	return ref.Dir() + "/" + ref.RelPath
+}
+
+// Dir returns the path of the version's content directory
+func (ref ContentRef) Dir() string {
+	return ref.Version + "/" + ref.ContentDir
+}
+
+// ParseContentRef parses the content path p. It returns an error wrapping
+// ErrNotContentPath if p isn't a valid path in the form
+// `{version}/{contentDirectory}/{path}`.
+func ParseContentRef(p string) (ContentRef, error) {
+	var ref ContentRef
+	if !validPath(p) {
+		return ref, &PathInvalidErr{p}
+	}
+	parts := strings.SplitN(p, "/", 3)
+	if len(parts) < 3 {
+		return ref, fmt.Errorf("%w: %s", ErrNotContentPath, p)
+	}
+	if _, _, err := versionParse(parts[0]); err != nil {
+		return ref, fmt.Errorf("%w: %s: %s", ErrNotContentPath, p, err.Error())
+	}
+	ref.Version, ref.ContentDir, ref.RelPath = parts[0], parts[1], parts[2]
+	return ref, nil
+}
+
+// contentRef returns a ContentRef for relPath in the content directory of
+// version vname (see versionContentDir).
+func (inv *Inventory) contentRef(vname string, relPath string) ContentRef {
+	return ContentRef{
+		Version:    vname,
+		ContentDir: inv.versionContentDir(vname),
+		RelPath:    relPath,
+	}
+}
@@ -0,0 +1,71 @@
+package internal_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestParseContentRef(t *testing.T) {
+	valid := map[string]internal.ContentRef{
+		`v1/content/a.txt`:       {Version: `v1`, ContentDir: `content`, RelPath: `a.txt`},
+		`v002/data/dir/b c.txt`:  {Version: `v002`, ContentDir: `data`, RelPath: `dir/b c.txt`},
+		`v10/content/v1/content`: {Version: `v10`, ContentDir: `content`, RelPath: `v1/content`},
+	}
+	for p, expected := range valid {
+		ref, err := internal.ParseContentRef(p)
+		if err != nil {
+			t.Errorf("%s: %v", p, err)
+			continue
+		}
+		if ref != expected {
+			t.Errorf("%s: got %+v", p, ref)
+		}
+		if ref.String() != p {
+			t.Errorf("expected %s, got %s", p, ref.String())
+		}
+	}
+	invalid := []string{`v1/content`, `content/v1/a.txt`, `v1/content/../a.txt`, `/v1/content/a.txt`, `v1\content\a.txt`}
+	for _, p := range invalid {
+		if _, err := internal.ParseContentRef(p); err == nil {
+			t.Errorf("expected error for %s", p)
+		}
+	}
+	if _, err := internal.ParseContentRef(`x/content/a.txt`); !errors.Is(err, internal.ErrNotContentPath) {
+		t.Errorf("expected ErrNotContentPath, got %v", err)
+	}
+}
+
+// content and logical paths are slash-separated on every platform, so they
+// must not be built with OS-specific path functions.
+func TestNoFilepathJoin(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		for name, file := range pkg.Files {
+			if strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name == "filepath" {
+					if sel.Sel.Name == "Join" || sel.Sel.Name == "Rel" {
+						t.Errorf("%s: filepath.%s", fset.Position(sel.Pos()), sel.Sel.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+}
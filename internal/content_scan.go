@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"hash"
+	"io/fs"
+)
+
+// ContentOption is used to configure ScanContent
+type ContentOption func(*contentOptions)
+
+type contentOptions struct {
+	failFast bool
+	alg      string
+}
+
+// WithAlg sets the digest algorithm used by ScanContent and ValidateContent.
+// The default is the inventory's digest algorithm. Other algorithms must be
+// supported (see RegisterDigestAlgorithm).
+func WithAlg(alg string) ContentOption {
+	return func(opts *contentOptions) {
+		opts.alg = alg
+	}
+}
+
+// WithFailFast configures ScanContent to return the first error reading or
+// digesting a content file, like Content.
+func WithFailFast() ContentOption {
+	return func(opts *contentOptions) {
+		opts.failFast = true
+	}
+}
+
+// ContentFailure is a content file or directory that couldn't be read.
+type ContentFailure struct {
+	Path string
+	Err  error
+}
+
+func (f ContentFailure) Error() string {
+	return f.Path + ": " + f.Err.Error()
+}
+
+func (f ContentFailure) Unwrap() error {
+	return f.Err
+}
+
+// ContentResult is returned by ScanContent.
+type ContentResult struct {
+	// Content maps digests to the content paths that were digested
+	Content DigestMap
+	// Failures are content files and directories that couldn't be read.
+	// Content in failed directories isn't included in Content.
+	Failures []ContentFailure
+}
+
+// ScanContent digests every file in the object's version content
+// directories. Unlike Content, files and directories that can't be read are
+// reported in the result's Failures and don't stop the scan (see
+// WithFailFast). Version content directories that don't exist are skipped.
+// An error is returned if ctx is canceled.
+func (obj *ObjectReader) ScanContent(ctx context.Context, opts ...ContentOption) (ContentResult, error) {
+	var result ContentResult
+	var conf contentOptions
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	obj = obj.withContext(ctx)
+	alg := obj.inventory.DigestAlgorithm
+	if conf.alg != "" {
+		alg = conf.alg
+	}
+	newH, err := newHash(alg)
+	if err != nil {
+		return result, err
+	}
+	onErr := func(name string, err error) error {
+		if conf.failFast {
+			return err
+		}
+		result.Failures = append(result.Failures, ContentFailure{Path: name, Err: err})
+		return nil
+	}
+	var files []string
+	sizes := map[string]int64{}
+	err = obj.walkContent(func(name string, d fs.DirEntry) error {
+		if obj.opts.metrics != nil || obj.opts.largestFirst {
+			info, err := d.Info()
+			if err != nil {
+				return onErr(name, err)
+			}
+			sizes[name] = info.Size()
+		}
+		files = append(files, name)
+		return nil
+	}, onErr)
+	if err != nil {
+		return result, err
+	}
+	if obj.opts.largestFirst {
+		sortLargestFirst(files, sizes)
+	}
+	var content PathIndex
+	each := func(name string, digest string, err error) error {
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return onErr(name, err)
+		}
+		obj.recordDigest(alg, sizes[name])
+		return content.Add(digest, name)
+	}
+	if err := digestPaths(ctx, obj.root, alg, newH, files, each); err != nil {
+		return result, err
+	}
+	result.Content = content.DigestMap()
+	return result, nil
+}
+
+// walkContent calls fn for each regular file in the object's version content
+// directories. Content directories that don't exist are skipped. If onErr is
+// not nil, it is called with errors reading directories below the content
+// directories: if it returns nil, the directory is skipped.
+func (obj *ObjectReader) walkContent(fn func(name string, d fs.DirEntry) error, onErr func(name string, err error) error) error {
+	for v := range obj.inventory.Versions {
+		contentDir := obj.inventory.contentRef(v, "").Dir()
+		err := fs.WalkDir(obj.root, contentDir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// contentDir may not exist - that's ok
+				if name == contentDir && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipDir
+				}
+				if onErr == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+					return err
+				}
+				if err := onErr(name, err); err != nil {
+					return err
+				}
+				return fs.SkipDir
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			return fn(name, d)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contentLargestFirst is digestContent for WithLargestFirst: content
+// directories are listed before any files are digested.
+func (obj *ObjectReader) contentLargestFirst(algs map[string]func() hash.Hash, fixity fixityMap) (DigestMap, fixityMap, error) {
+	alg := obj.inventory.DigestAlgorithm
+	var files []string
+	sizes := map[string]int64{}
+	err := obj.walkContent(func(name string, d fs.DirEntry) error {
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, name)
+		sizes[name] = info.Size()
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	sortLargestFirst(files, sizes)
+	var content PathIndex
+	digests := fixity.empty()
+	each := func(name string, sums map[string]string, err error) error {
+		if err != nil {
+			return err
+		}
+		obj.recordDigests(sums, sizes[name])
+		digests.add(name, sums)
+		return content.Add(sums[alg], name)
+	}
+	if err := digestPathsAlgs(obj.root.context(), obj.root, algs, files, each); err != nil {
+		return nil, nil, err
+	}
+	return content.DigestMap(), digests, nil
+}
@@ -0,0 +1,69 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+var errUnreadable = errors.New("unreadable")
+
+// failFS returns errUnreadable when opening the named files or directories
+type failFS struct {
+	fs.FS
+	fail map[string]bool
+}
+
+func (fsys *failFS) Open(name string) (fs.File, error) {
+	if fsys.fail[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errUnreadable}
+	}
+	return fsys.FS.Open(name)
+}
+
+func TestScanContent(t *testing.T) {
+	gen := ocfltest.NewObject(t, ocfltest.WithVersions(2), ocfltest.WithFiles(6))
+	fsys := &failFS{FS: gen.FS, fail: map[string]bool{
+		"v1/content/dir-0":              true, // 2 files
+		"v2/content/dir-1/file-2-1.dat": true,
+	}}
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := obj.ScanContent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Failures) != 2 {
+		t.Fatalf("expected 2 failures, got %v", result.Failures)
+	}
+	for _, f := range result.Failures {
+		if !fsys.fail[f.Path] || !errors.Is(f, errUnreadable) {
+			t.Errorf("unexpected failure: %v", f)
+		}
+	}
+	paths, err := result.Content.Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 12-3 {
+		t.Errorf("expected 9 content paths, got %d", len(paths))
+	}
+
+	// fail fast
+	if _, err := obj.ScanContent(context.Background(), internal.WithFailFast()); !errors.Is(err, errUnreadable) {
+		t.Errorf("expected fail fast error, got %v", err)
+	}
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := obj.ScanContent(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
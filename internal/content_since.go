@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ContentSinceOption is used to configure ValidateContentSince
+type ContentSinceOption func(*contentSinceOptions)
+
+type contentSinceOptions struct {
+	sample float64
+}
+
+// WithBackfillSample configures ValidateContentSince to also check a
+// fraction (between 0 and 1) of the content from versions up to and
+// including sinceVersion. The sample is deterministic for a given object
+// head.
+func WithBackfillSample(fraction float64) ContentSinceOption {
+	return func(opts *contentSinceOptions) {
+		opts.sample = fraction
+	}
+}
+
+// ContentCoverage describes the content checked by ValidateContentSince.
+type ContentCoverage struct {
+	Since string
+	// Versions lists the versions whose content was fully checked
+	Versions []string
+	// Sampled is the number of content files from older versions that were
+	// checked, out of Older.
+	Sampled int
+	Older   int
+}
+
+// ValidateContentSince checks the digests of manifest content paths in
+// version directories newer than sinceVersion. Versions are ordered by
+// version number, not by their created timestamps. Missing or modified
+// content is reported as E092. The returned ContentCoverage records what was
+// checked. An error is returned if sinceVersion isn't a version of the
+// object or if the check couldn't be completed.
+func (obj *ObjectReader) ValidateContentSince(ctx context.Context, sinceVersion string, opts ...ContentSinceOption) (ValidationResult, ContentCoverage, error) {
+	result := &validationResult{}
+	result.setConformance()
+	coverage := ContentCoverage{Since: sinceVersion}
+	var conf contentSinceOptions
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if _, ok := obj.inventory.Versions[sinceVersion]; !ok {
+		return result, coverage, fmt.Errorf("%w: %s", ErrVersionNotFound, sinceVersion)
+	}
+	since, _, err := versionParse(sinceVersion)
+	if err != nil {
+		return result, coverage, err
+	}
+	manifest, err := obj.inventory.Manifest.Normalize()
+	if err != nil {
+		return result, coverage, err
+	}
+	digests, err := manifest.Paths()
+	if err != nil {
+		return result, coverage, err
+	}
+	for v := range obj.inventory.Versions {
+		if num, _, err := versionParse(v); err == nil && num > since {
+			coverage.Versions = append(coverage.Versions, v)
+		}
+	}
+	sortVersions(coverage.Versions)
+	var newer, older []string
+	for p := range digests {
+		v := strings.SplitN(p, "/", 2)[0]
+		if num, _, err := versionParse(v); err == nil && num > since {
+			newer = append(newer, p)
+			continue
+		}
+		older = append(older, p)
+	}
+	coverage.Older = len(older)
+	sample := obj.backfillSample(older, conf.sample)
+	coverage.Sampled = len(sample)
+	newH, err := newHash(obj.inventory.DigestAlgorithm)
+	if err != nil {
+		return result, coverage, err
+	}
+	var failed []error
+	each := func(name string, sum string, err error) error {
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			failed = append(failed, fmt.Errorf(`manifest content not found: %s`, name))
+			return nil
+		}
+		if sum != digests[name] {
+			failed = append(failed, fmt.Errorf(`content digest doesn't match manifest: %s`, name))
+		}
+		return nil
+	}
+	err = digestPaths(ctx, obj.root, obj.inventory.DigestAlgorithm, newH, append(newer, sample...), each)
+	if err != nil {
+		return result, coverage, err
+	}
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].Error() < failed[j].Error()
+	})
+	for _, err := range failed {
+		result.AddFatal(err, &ErrE092)
+	}
+	return result, coverage, nil
+}
+
+// backfillSample returns a deterministic sample of paths with size
+// fraction*len(paths), rounded up. Paths are ranked by a hash of the path
+// and the object head.
+func (obj *ObjectReader) backfillSample(paths []string, fraction float64) []string {
+	if fraction <= 0 || len(paths) == 0 {
+		return nil
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	n := int(math.Ceil(fraction * float64(len(paths))))
+	rank := make(map[string]uint64, len(paths))
+	for _, p := range paths {
+		h := fnv.New64a()
+		h.Write([]byte(obj.inventory.Head + "/" + p))
+		rank[p] = h.Sum64()
+	}
+	sorted := append([]string{}, paths...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if rank[sorted[i]] != rank[sorted[j]] {
+			return rank[sorted[i]] < rank[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted[:n]
+}
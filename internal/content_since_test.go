@@ -0,0 +1,81 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestValidateContentSince(t *testing.T) {
+	ctx := context.Background()
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := obj.ValidateContentSince(ctx, `v9`); !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+	// corrupt v1 content: not checked since v1
+	fsys[`v1/content/image.tiff`] = &fstest.MapFile{Data: []byte(`corrupt`)}
+	result, coverage, err := obj.ValidateContentSince(ctx, `v1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid() {
+		t.Error(result.Fatal())
+	}
+	expected := internal.ContentCoverage{
+		Since:    `v1`,
+		Versions: []string{`v2`, `v3`},
+		Older:    3,
+	}
+	if !reflect.DeepEqual(coverage, expected) {
+		t.Errorf("expected %+v, got %+v", expected, coverage)
+	}
+	// the full backfill sample includes the corrupt file
+	result, coverage, err = obj.ValidateContentSince(ctx, `v1`, internal.WithBackfillSample(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coverage.Sampled != 3 {
+		t.Errorf("expected 3 sampled files, got %d", coverage.Sampled)
+	}
+	if result.Valid() || !errors.Is(result.Fatal()[0], &internal.ErrE092) {
+		t.Errorf("expected E092, got %v", result.Fatal())
+	}
+	// partial samples are deterministic
+	_, c1, err := obj.ValidateContentSince(ctx, `v1`, internal.WithBackfillSample(0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, c2, err := obj.ValidateContentSince(ctx, `v1`, internal.WithBackfillSample(0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1.Sampled != 2 || !reflect.DeepEqual(c1, c2) {
+		t.Errorf("unexpected sample coverage: %+v, %+v", c1, c2)
+	}
+	// missing content in a newer version
+	delete(fsys, `v2/content/foo/bar.xml`)
+	result, _, err = obj.ValidateContentSince(ctx, `v1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid() || !errors.Is(result.Fatal()[0], &internal.ErrE092) {
+		t.Errorf("expected E092, got %v", result.Fatal())
+	}
+	// nothing is newer than the head
+	_, coverage, err = obj.ValidateContentSince(ctx, `v3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage.Versions) != 0 {
+		t.Errorf("expected no versions checked, got %v", coverage.Versions)
+	}
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateContent checks the digests of the object's content files. By
+// default, content is checked against the manifest. With WithAlg, content is
+// checked against the inventory's fixity values for the algorithm, or the
+// manifest if it is the inventory's digest algorithm. Files that are missing
+// or have different digests are reported as E092 (manifest) or E093
+// (fixity) errors. An error wrapping ErrUnsupportedAlgorithm is returned if
+// the algorithm isn't supported, and an error is returned if the inventory
+// has no fixity values for it or if ctx is canceled.
+func (obj *ObjectReader) ValidateContent(ctx context.Context, opts ...ContentOption) (ValidationResult, error) {
+	result := &validationResult{}
+	result.setConformance()
+	defer DefaultProfile.apply(result)
+	var conf contentOptions
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	inv := obj.inventory
+	alg, expected, code := inv.DigestAlgorithm, inv.Manifest, &ErrE092
+	if conf.alg != "" && conf.alg != inv.DigestAlgorithm {
+		alg, expected, code = conf.alg, inv.Fixity[conf.alg], &ErrE093
+	}
+	newH, err := newHash(alg)
+	if err != nil {
+		return result, err
+	}
+	if expected == nil {
+		return result, fmt.Errorf("inventory has no %s fixity", alg)
+	}
+	expected, err = expected.Normalize()
+	if err != nil {
+		return result, err
+	}
+	paths, err := expected.Paths()
+	if err != nil {
+		return result, err
+	}
+	names := make([]string, 0, len(paths))
+	for p := range paths {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	each := func(name string, digest string, err error) error {
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			result.AddFatal(fmt.Errorf("%s: %w", name, err), code)
+			return nil
+		}
+		if !strings.EqualFold(digest, paths[name]) {
+			result.AddFatal(fmt.Errorf("%s digest mismatch: %s", alg, name), code)
+		}
+		return nil
+	}
+	obj = obj.withContext(ctx)
+	if err := digestPaths(ctx, obj.root, alg, newH, names, each); err != nil {
+		return result, err
+	}
+	return result, nil
+}
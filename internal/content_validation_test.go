@@ -0,0 +1,121 @@
+package internal_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// legacyFixityObject returns the spec-ex-full fixture with crc32 and adler32
+// fixity blocks. The adler32 values are fake.
+func legacyFixityObject(t *testing.T) fstest.MapFS {
+	t.Helper()
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	crc := map[string]interface{}{}
+	adler := map[string]interface{}{}
+	for i, name := range []string{`v1/content/foo/bar.xml`, `v1/content/image.tiff`, `v2/content/foo/bar.xml`} {
+		sum := crc32.ChecksumIEEE(fsys[name].Data)
+		crc[hex.EncodeToString([]byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)})] = []string{name}
+		adler[strings.Repeat(string(rune('a'+i)), 8)] = []string{name}
+	}
+	fixity := inv["fixity"].(map[string]interface{})
+	fixity["crc32"] = crc
+	fixity["adler32"] = adler
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v3`, inv)
+	return fsys
+}
+
+func TestUnsupportedFixity(t *testing.T) {
+	fsys := legacyFixityObject(t)
+	// one warning for the unsupported algorithm
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	var warnings []string
+	for _, w := range result.Warning() {
+		if errors.Is(w, internal.ErrUnsupportedAlgorithm) && strings.Contains(w.Error(), "adler32") {
+			warnings = append(warnings, w.Error())
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "(3 entries)") {
+		t.Errorf("expected one adler32 warning with entry count, got %v", warnings)
+	}
+	// round trip
+	inv, err := internal.ReadInventory(bytes.NewReader(fsys[`inventory.json`].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var before, after map[string]interface{}
+	json.Unmarshal(fsys[`inventory.json`].Data, &before)
+	json.Unmarshal(out, &after)
+	if !reflect.DeepEqual(before["fixity"], after["fixity"]) {
+		t.Error("fixity changed after round trip")
+	}
+	// FixityFor
+	fixity := inv.FixityFor(`v1/content/image.tiff`)
+	for _, alg := range []string{`md5`, `sha1`, `crc32`, `adler32`} {
+		if fixity[alg] == "" {
+			t.Errorf("FixityFor: missing %s in %v", alg, fixity)
+		}
+	}
+	// ValidateContent
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = obj.ValidateContent(context.Background(), internal.WithAlg(`adler32`))
+	if !errors.Is(err, internal.ErrUnsupportedAlgorithm) {
+		t.Errorf("expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+	internal.RegisterDigestAlgorithm(`crc32`, func() hash.Hash { return crc32.NewIEEE() })
+	result, err = obj.ValidateContent(context.Background(), internal.WithAlg(`crc32`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	// crc32 is checked by full validation
+	result = internal.ValidateObject(fsys)
+	for _, w := range result.Warning() {
+		if strings.Contains(w.Error(), "crc32") {
+			t.Errorf("unexpected warning: %v", w)
+		}
+	}
+	fsys[`v1/content/image.tiff`].Data = []byte("changed")
+	result, err = obj.ValidateContent(context.Background(), internal.WithAlg(`crc32`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Fatal()) != 1 || result.Fatal()[0].Code() != `E093` {
+		t.Errorf("expected E093, got %v", result.Fatal())
+	}
+	result, err = obj.ValidateContent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Fatal()) != 1 || result.Fatal()[0].Code() != `E092` {
+		t.Errorf("expected E092, got %v", result.Fatal())
+	}
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"io/fs"
+)
+
+// OpenContextFS is implemented by backends that can cancel requests. If an
+// object's FS implements it, OpenContext is used to open files during
+// operations that take a context.
+type OpenContextFS interface {
+	fs.FS
+	OpenContext(ctx context.Context, name string) (fs.File, error)
+}
+
+// Open implements fs.FS. If the root has a context, it is checked before
+// opening the file and passed to the FS if it implements OpenContextFS and
+// its Capabilities don't say otherwise.
+func (root objectRoot) Open(name string) (fs.File, error) {
+	if root.ctx == nil {
+		return root.FS.Open(name)
+	}
+	if err := root.ctx.Err(); err != nil {
+		return nil, &fs.PathError{Op: `open`, Path: name, Err: err}
+	}
+	if ctxFS, ok := root.FS.(OpenContextFS); ok && CapabilitiesOf(root.FS).SupportsContext {
+		return ctxFS.OpenContext(root.ctx, name)
+	}
+	return root.FS.Open(name)
+}
+
+// context returns the root's context or context.Background
+func (root objectRoot) context() context.Context {
+	if root.ctx == nil {
+		return context.Background()
+	}
+	return root.ctx
+}
+
+// withContext returns a shallow copy of obj that uses ctx for I/O.
+func (obj *ObjectReader) withContext(ctx context.Context) *ObjectReader {
+	cp := *obj
+	cp.root.ctx = ctx
+	return &cp
+}
+
+// NewObjectReaderContext is like NewObjectReader, but ctx is used while
+// reading the object's declaration and inventory. The ObjectReader doesn't
+// retain ctx.
+func NewObjectReaderContext(ctx context.Context, root fs.FS, opts ...ObjectOption) (*ObjectReader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return newObjectReader(ctx, root, opts...)
+}
+
+// ContentContext is like Content, but ctx is checked between files and
+// used to open them.
+func (obj *ObjectReader) ContentContext(ctx context.Context) (DigestMap, error) {
+	return obj.withContext(ctx).Content()
+}
+
+// ValidateContext is like Validate, but validation stops if ctx is
+// canceled. The context's error is included in the result's fatal errors.
+func (obj *ObjectReader) ValidateContext(ctx context.Context) ValidationResult {
+	return obj.withContext(ctx).validateAll()
+}
+
+// ValidateObjectContext is like ValidateObject, but validation stops if ctx
+// is canceled.
+func ValidateObjectContext(ctx context.Context, root fs.FS, opts ...ObjectOption) ValidationResult {
+	vr := &validationResult{}
+	obj, err := NewObjectReaderContext(ctx, root, opts...)
+	if err != nil {
+		var o objectOptions
+		for _, opt := range opts {
+			opt(&o)
+		}
+		vr.stop(err)
+		o.getProfile().apply(vr)
+		vr.setConformance()
+		return vr
+	}
+	vr.Merge(obj.ValidateContext(ctx))
+	return vr
+}
@@ -0,0 +1,76 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// ctxFS records calls to OpenContext
+type ctxFS struct {
+	fstest.MapFS
+	calls int64
+}
+
+func (fsys *ctxFS) OpenContext(ctx context.Context, name string) (fs.File, error) {
+	atomic.AddInt64(&fsys.calls, 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fsys.MapFS.Open(name)
+}
+
+func TestContextCanceled(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := internal.NewObjectReaderContext(ctx, fsys); !errors.Is(err, context.Canceled) {
+		t.Errorf("NewObjectReaderContext: expected context.Canceled, got %v", err)
+	}
+	result := internal.ValidateObjectContext(ctx, fsys)
+	if result.Valid() {
+		t.Fatal("expected validation with canceled context to fail")
+	}
+	if !errors.Is(result.Fatal()[0], context.Canceled) {
+		t.Errorf("ValidateObjectContext: expected context.Canceled, got %v", result.Fatal())
+	}
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.ContentContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ContentContext: expected context.Canceled, got %v", err)
+	}
+	result = obj.ValidateContext(ctx)
+	if len(result.Fatal()) == 0 || !errors.Is(result.Fatal()[0], context.Canceled) {
+		t.Errorf("ValidateContext: expected context.Canceled, got %v", result.Fatal())
+	}
+	// the ObjectReader is still usable
+	if result := obj.ValidateContext(context.Background()); !result.Valid() {
+		t.Error(result.Fatal())
+	}
+}
+
+func TestOpenContextFS(t *testing.T) {
+	fsys := &ctxFS{MapFS: loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))}
+	obj, err := internal.NewObjectReaderContext(context.Background(), fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt64(&fsys.calls) == 0 {
+		t.Fatal("expected OpenContext to be used by NewObjectReaderContext")
+	}
+	calls := atomic.LoadInt64(&fsys.calls)
+	if result := obj.ValidateContext(context.Background()); !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	if atomic.LoadInt64(&fsys.calls) == calls {
+		t.Error("expected OpenContext to be used by ValidateContext")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"io/fs"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -48,9 +49,15 @@ func (d *DigestInvalidErr) Error() string {
 // PathConflictErr a path conflic in the DigestMap
 type PathConflictErr struct {
 	Path string
+	// Conflict is a path that has Path as a parent directory, if Path is
+	// used as both a file and a directory.
+	Conflict string
 }
 
 func (p *PathConflictErr) Error() string {
+	if p.Conflict != "" {
+		return "path is both a file and a directory: " + p.Path + " conflicts with " + p.Conflict
+	}
 	return "duplicate Path: " + string(p.Path)
 }
 
@@ -60,7 +67,7 @@ type PathInvalidErr struct {
 }
 
 func (p *PathInvalidErr) Error() string {
-	return "invalid Path: " + string(p.Path)
+	return "invalid Path: " + strconv.Quote(p.Path)
 }
 
 // DigestMap is a data structure for Content-Addressable-Storage.
@@ -73,8 +80,8 @@ func (dm *DigestMap) Add(digest string, path string) error {
 	if !validPath(path) {
 		return &PathInvalidErr{path}
 	}
-	if dm.GetDigest(path) != `` {
-		return &PathConflictErr{path}
+	if _, exists := dm.PathDigest(path); exists {
+		return &PathConflictErr{Path: path}
 	}
 	if *dm == nil {
 		*dm = DigestMap{}
@@ -83,15 +90,24 @@ func (dm *DigestMap) Add(digest string, path string) error {
 	return nil
 }
 
+// GetDigest returns the digest for path p, or an empty string if p isn't in
+// the DigestMap.
 func (dm DigestMap) GetDigest(p string) string {
+	d, _ := dm.PathDigest(p)
+	return d
+}
+
+// PathDigest returns the digest for path p, and false if p isn't in the
+// DigestMap. It scans every path; use a PathIndex for repeated lookups.
+func (dm DigestMap) PathDigest(p string) (string, bool) {
 	for d, paths := range dm {
 		for _, path := range paths {
 			if p == path {
-				return d
+				return d, true
 			}
 		}
 	}
-	return ""
+	return "", false
 }
 
 // Paths returns a mapping between all files and their digests
@@ -101,7 +117,7 @@ func (dm DigestMap) Paths() (map[string]string, error) {
 	for d, paths := range dm {
 		for _, p := range paths {
 			if _, exists := inv[p]; exists {
-				return nil, &PathConflictErr{p}
+				return nil, &PathConflictErr{Path: p}
 			}
 			inv[p] = d
 		}
@@ -122,7 +138,7 @@ func (dm DigestMap) Normalize() (DigestMap, error) {
 		return nil, errors.New(`digest map cannot be nil`)
 	}
 	newDM := make(DigestMap)
-	allDirs := make(map[string]bool)
+	allDirs := make(map[string]string) // parent directory -> path
 	for d, paths := range dm {
 		if !digestRegexp.MatchString(d) {
 			return nil, &DigestInvalidErr{d}
@@ -138,15 +154,15 @@ func (dm DigestMap) Normalize() (DigestMap, error) {
 			}
 			newDM[lowerD][i] = p
 			for _, dir := range parentDirs(p) {
-				allDirs[dir] = true
+				allDirs[dir] = p
 			}
 		}
 	}
 	// no paths should be dirs
 	for _, paths := range newDM {
 		for _, p := range paths {
-			if _, exists := allDirs[p]; exists {
-				return nil, &PathConflictErr{p}
+			if child, exists := allDirs[p]; exists {
+				return nil, &PathConflictErr{Path: p, Conflict: child}
 			}
 		}
 	}
@@ -156,7 +172,7 @@ func (dm DigestMap) Normalize() (DigestMap, error) {
 // validPath returns
 func validPath(p string) bool {
 	// fs.ValidPath is nearly perfect for OCFL
-	if p == "." {
+	if p == "." || hasControlChar(p) {
 		return false
 	}
 	return fs.ValidPath(p)
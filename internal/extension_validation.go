@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of extensions with built-in validators
+const (
+	VersionStatsExtension = `version-stats`
+	MutableHeadExtension  = `0005-mutable-head`
+)
+
+// ExtensionValidator validates the contents of an object extension
+// directory. root is the object root, dir is the extension directory's path
+// in root, and inv is the object's root inventory. Errors are reported as
+// validation warnings: extension contents don't affect the object's
+// validity, but a Profile can promote them to errors.
+type ExtensionValidator func(root fs.FS, dir string, inv *Inventory) []error
+
+var extensionValidators = struct {
+	sync.RWMutex
+	m map[string]ExtensionValidator
+}{
+	m: map[string]ExtensionValidator{
+		VersionStatsExtension: validateVersionStatsExt,
+		MutableHeadExtension:  validateMutableHeadExt,
+	},
+}
+
+// RegisterExtensionValidator sets the validator for extension directories
+// named name, replacing any existing validator. Extension directories
+// without a validator are reported with a W013 warning.
+func RegisterExtensionValidator(name string, v ExtensionValidator) {
+	extensionValidators.Lock()
+	defer extensionValidators.Unlock()
+	extensionValidators.m[name] = v
+}
+
+func getExtensionValidator(name string) (ExtensionValidator, bool) {
+	extensionValidators.RLock()
+	defer extensionValidators.RUnlock()
+	v, ok := extensionValidators.m[name]
+	return v, ok
+}
+
+// validateExtensions runs extension validators for each extension
+// directory, adding their errors to result as warnings.
+func (obj *ObjectReader) validateExtensions(result *validationResult) error {
+	items, err := fs.ReadDir(obj.root, extensionsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, item := range items {
+		name := item.Name()
+		validator, ok := getExtensionValidator(name)
+		if !ok {
+			err := fmt.Errorf("unregistered extension: %s", name)
+			result.AddWarn(err, &ErrW013)
+			continue
+		}
+		for _, err := range validator(obj.root, path.Join(extensionsDir, name), obj.inventory) {
+			result.AddWarn(fmt.Errorf("extension %s: %w", name, err), nil)
+		}
+	}
+	return nil
+}
+
+// validateVersionStatsExt checks the version stats file is the only file in
+// the extension directory and that its entries are for versions in inv.
+func validateVersionStatsExt(root fs.FS, dir string, inv *Inventory) []error {
+	var errs []error
+	items, err := fs.ReadDir(root, dir)
+	if err != nil {
+		return []error{err}
+	}
+	for _, item := range items {
+		if item.Name() != path.Base(VersionStatsPath) {
+			errs = append(errs, fmt.Errorf("unexpected file: %s", item.Name()))
+		}
+	}
+	data, err := fs.ReadFile(root, path.Join(dir, path.Base(VersionStatsPath)))
+	if err != nil {
+		return append(errs, err)
+	}
+	var statsFile VersionStatsFile
+	if err := json.Unmarshal(data, &statsFile); err != nil {
+		return append(errs, fmt.Errorf("reading stats: %w", err))
+	}
+	if _, ok := inv.Versions[statsFile.Head]; !ok {
+		errs = append(errs, fmt.Errorf("stats head is not an object version: %q", statsFile.Head))
+	}
+	vnames := make([]string, 0, len(statsFile.Versions))
+	for vname := range statsFile.Versions {
+		vnames = append(vnames, vname)
+	}
+	sort.Strings(vnames)
+	for _, vname := range vnames {
+		stats := statsFile.Versions[vname]
+		if _, ok := inv.Versions[vname]; !ok {
+			errs = append(errs, fmt.Errorf("stats for version not in object: %s", vname))
+			continue
+		}
+		if stats.Files < 0 || stats.Bytes < 0 {
+			errs = append(errs, fmt.Errorf("negative stats for %s", vname))
+		}
+	}
+	return errs
+}
+
+// validateMutableHeadExt checks the mutable head inventory is valid, that
+// it is for a version after the object's head, and that its manifest
+// content exists.
+func validateMutableHeadExt(root fs.FS, dir string, inv *Inventory) []error {
+	headDir := path.Join(dir, `head`)
+	headRoot := &objectRoot{FS: root}
+	head, err := headRoot.readInventory(headDir, true)
+	if err != nil {
+		return []error{fmt.Errorf("reading head inventory: %w", err)}
+	}
+	var errs []error
+	if head.ID != inv.ID {
+		errs = append(errs, fmt.Errorf("head inventory id is %q, object id is %q", head.ID, inv.ID))
+	}
+	if !headAdvanced(inv.Head, head.Head) {
+		errs = append(errs, fmt.Errorf("head inventory version %s doesn't follow object head %s", head.Head, inv.Head))
+	}
+	paths, err := head.Manifest.Paths()
+	if err != nil {
+		return append(errs, err)
+	}
+	objPaths, err := inv.Manifest.Paths()
+	if err != nil {
+		return append(errs, err)
+	}
+	contentPaths := make([]string, 0, len(paths))
+	for p := range paths {
+		contentPaths = append(contentPaths, p)
+	}
+	sort.Strings(contentPaths)
+	for _, p := range contentPaths {
+		if !strings.HasPrefix(p, headDir+"/") {
+			if _, ok := objPaths[p]; !ok {
+				errs = append(errs, fmt.Errorf("head manifest content not in object: %s", p))
+			}
+			continue
+		}
+		if _, err := fs.Stat(root, p); err != nil {
+			errs = append(errs, fmt.Errorf("head manifest content not found: %s", p))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,156 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// extensionWarnings validates fsys and returns warnings for extension name
+func extensionWarnings(t *testing.T, fsys fs.FS, name string) []string {
+	t.Helper()
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	var warns []string
+	for _, w := range result.Warning() {
+		if strings.Contains(w.Error(), "extension "+name) {
+			warns = append(warns, w.Error())
+		}
+	}
+	return warns
+}
+
+func TestVersionStatsExtension(t *testing.T) {
+	statsPath := internal.VersionStatsPath
+	table := map[string]struct {
+		files map[string]string
+		warns int
+	}{
+		`valid`: {
+			files: map[string]string{
+				statsPath: `{"head":"v3","versions":{"v1":{"files":1,"bytes":2}}}`,
+			},
+		},
+		`unknown version`: {
+			files: map[string]string{
+				statsPath: `{"head":"v9","versions":{"v1":{"files":1,"bytes":2},"v9":{"files":1,"bytes":2}}}`,
+			},
+			warns: 2,
+		},
+		`extra file and invalid json`: {
+			files: map[string]string{
+				statsPath:                    `{"head":`,
+				`extensions/version-stats/x`: `x`,
+			},
+			warns: 2,
+		},
+	}
+	for name, tcase := range table {
+		t.Run(name, func(t *testing.T) {
+			fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+			for name, data := range tcase.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(data)}
+			}
+			warns := extensionWarnings(t, fsys, internal.VersionStatsExtension)
+			if len(warns) != tcase.warns {
+				t.Errorf("expected %d warnings, got %d: %v", tcase.warns, len(warns), warns)
+			}
+		})
+	}
+}
+
+// addMutableHead adds a mutable head extension to fsys with a new version
+// that adds one file. The head inventory can be edited with fn.
+func addMutableHead(t *testing.T, fsys fstest.MapFS, fn func(inv map[string]interface{})) {
+	t.Helper()
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("mutable head content")
+	digest := sha512Hex(data)
+	contentPath := `extensions/0005-mutable-head/head/content/r1/new.txt`
+	fsys[contentPath] = &fstest.MapFile{Data: data}
+	inv["manifest"].(map[string]interface{})[digest] = []interface{}{contentPath}
+	versions := inv["versions"].(map[string]interface{})
+	v4 := map[string]interface{}{}
+	for k, v := range versions["v3"].(map[string]interface{}) {
+		v4[k] = v
+	}
+	state := map[string]interface{}{digest: []interface{}{"new.txt"}}
+	for d, paths := range v4["state"].(map[string]interface{}) {
+		state[d] = paths
+	}
+	v4["state"] = state
+	versions["v4"] = v4
+	inv["head"] = "v4"
+	if fn != nil {
+		fn(inv)
+	}
+	setInventory(t, fsys, `extensions/0005-mutable-head/head`, inv)
+}
+
+func TestMutableHeadExtension(t *testing.T) {
+	t.Run(`valid`, func(t *testing.T) {
+		fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+		addMutableHead(t, fsys, nil)
+		if warns := extensionWarnings(t, fsys, internal.MutableHeadExtension); len(warns) > 0 {
+			t.Error(warns)
+		}
+	})
+	t.Run(`missing content`, func(t *testing.T) {
+		fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+		addMutableHead(t, fsys, nil)
+		delete(fsys, `extensions/0005-mutable-head/head/content/r1/new.txt`)
+		warns := extensionWarnings(t, fsys, internal.MutableHeadExtension)
+		if len(warns) != 1 || !strings.Contains(warns[0], `content not found`) {
+			t.Errorf("unexpected warnings: %v", warns)
+		}
+	})
+	t.Run(`head not advanced`, func(t *testing.T) {
+		fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+		addMutableHead(t, fsys, func(inv map[string]interface{}) {
+			versions := inv["versions"].(map[string]interface{})
+			versions["v3"] = versions["v4"]
+			delete(versions, "v4")
+			inv["head"] = "v3"
+		})
+		warns := extensionWarnings(t, fsys, internal.MutableHeadExtension)
+		if len(warns) != 1 || !strings.Contains(warns[0], `doesn't follow object head`) {
+			t.Errorf("unexpected warnings: %v", warns)
+		}
+	})
+}
+
+func TestUnregisteredExtension(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	fsys[`extensions/example-unregistered/file`] = &fstest.MapFile{}
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	var found bool
+	for _, w := range result.Warning() {
+		found = found || errors.Is(w, &internal.ErrW013)
+	}
+	if !found {
+		t.Error("expected W013 warning")
+	}
+	internal.RegisterExtensionValidator(`example-registered`, func(root fs.FS, dir string, inv *internal.Inventory) []error {
+		return []error{fmt.Errorf("checked %s for %s", dir, inv.ID)}
+	})
+	fsys[`extensions/example-registered/file`] = &fstest.MapFile{}
+	warns := extensionWarnings(t, fsys, `example-registered`)
+	if len(warns) != 1 || !strings.Contains(warns[0], `checked extensions/example-registered`) {
+		t.Errorf("unexpected warnings: %v", warns)
+	}
+}
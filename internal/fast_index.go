@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/srerickson/checksum"
+)
+
+// CRC32C is a non-cryptographic checksum algorithm for FastIndex. It is
+// not an OCFL digest algorithm and can't be used in inventories.
+const CRC32C = `crc32c`
+
+// fastHash returns a constructor for non-cryptographic algorithms used by
+// FastIndex. These are deliberately not available from newHash.
+func fastHash(alg string) (func() hash.Hash, error) {
+	switch alg {
+	case CRC32C:
+		table := crc32.MakeTable(crc32.Castagnoli)
+		return func() hash.Hash { return crc32.New(table) }, nil
+	}
+	return nil, fmt.Errorf(`unknown fast index algorithm: %s`, alg)
+}
+
+// FastIndex holds non-cryptographic checksums of an object's content files
+// for quick integrity sweeps. It can be serialized as JSON and stored
+// anywhere, including outside the object.
+type FastIndex struct {
+	Algorithm string            `json:"algorithm"`
+	Head      string            `json:"head"`    // object head when created
+	Entries   map[string]string `json:"entries"` // content path -> checksum
+}
+
+// FastReport is the result of ObjectReader.VerifyFast.
+type FastReport struct {
+	Checked int // number of content files checked
+	// Corrupt lists content paths that didn't match the index and failed a
+	// full check against the manifest digest.
+	Corrupt []string
+	// Missing lists content paths that couldn't be found.
+	Missing []string
+	// Stale lists content paths that aren't in the index, or didn't match
+	// the index but passed a full check against the manifest.
+	Stale []string
+}
+
+// Valid returns true if no corrupt or missing content was found.
+func (r FastReport) Valid() bool {
+	return len(r.Corrupt) == 0 && len(r.Missing) == 0
+}
+
+// BuildFastIndex returns a FastIndex with alg checksums for every content
+// path in the manifest. Currently, alg must be CRC32C.
+func (obj *ObjectReader) BuildFastIndex(ctx context.Context, alg string) (FastIndex, error) {
+	idx := FastIndex{
+		Algorithm: alg,
+		Head:      obj.inventory.Head,
+		Entries:   map[string]string{},
+	}
+	each := func(name string, sum string, err error) error {
+		if err != nil {
+			return err
+		}
+		idx.Entries[name] = sum
+		return nil
+	}
+	if err := obj.fastDigests(ctx, alg, each); err != nil {
+		return idx, err
+	}
+	return idx, nil
+}
+
+// VerifyFast checks every content path in the manifest against idx. Files
+// that don't match the index are checked again with the inventory's digest
+// algorithm before they are reported as corrupt, so changes to the object
+// since the index was built aren't reported as corruption.
+func (obj *ObjectReader) VerifyFast(ctx context.Context, idx FastIndex) (FastReport, error) {
+	var report FastReport
+	obj = obj.withContext(ctx)
+	digests, err := obj.inventory.Manifest.Paths()
+	if err != nil {
+		return report, err
+	}
+	newH, err := newHash(obj.inventory.DigestAlgorithm)
+	if err != nil {
+		return report, err
+	}
+	var recheck []string
+	each := func(name string, sum string, err error) error {
+		report.Checked++
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			report.Missing = append(report.Missing, name)
+			return nil
+		}
+		if expected, ok := idx.Entries[name]; !ok || expected != sum {
+			recheck = append(recheck, name)
+		}
+		return nil
+	}
+	if err := obj.fastDigests(ctx, idx.Algorithm, each); err != nil {
+		return report, err
+	}
+	// files that don't match the index are digested concurrently, after
+	// the fast checks
+	full := func(name string, sum string, err error) error {
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			report.Missing = append(report.Missing, name)
+			return nil
+		}
+		if !strings.EqualFold(sum, digests[name]) {
+			report.Corrupt = append(report.Corrupt, name)
+			return nil
+		}
+		report.Stale = append(report.Stale, name)
+		return nil
+	}
+	if err := digestPaths(ctx, obj.root, obj.inventory.DigestAlgorithm, newH, recheck, full); err != nil {
+		return report, err
+	}
+	sort.Strings(report.Corrupt)
+	sort.Strings(report.Missing)
+	sort.Strings(report.Stale)
+	return report, nil
+}
+
+// fastDigests calls each with the alg checksum of every manifest path, or
+// the error digesting it.
+func (obj *ObjectReader) fastDigests(ctx context.Context, alg string, each func(string, string, error) error) error {
+	newH, err := fastHash(alg)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for _, ps := range obj.inventory.Manifest {
+		paths = append(paths, ps...)
+	}
+	return digestPaths(ctx, obj.root, alg, newH, paths, each)
+}
+
+// digestPaths concurrently digests the named files in fsys, calling each
+// with the file's name and digest, or the error digesting it. If each
+// returns an error, digesting stops and the error is returned.
+func digestPaths(ctx context.Context, fsys fs.FS, alg string, newH func() hash.Hash, paths []string, each func(string, string, error) error) error {
+	algs := map[string]func() hash.Hash{alg: newH}
+	return digestPathsAlgs(ctx, fsys, algs, paths, func(name string, sums map[string]string, err error) error {
+		return each(name, sums[alg], err)
+	})
+}
+
+// digestPathsAlgs is like digestPaths, but each file is digested with every
+// algorithm in algs as it is read. each is called with the file's digests by
+// algorithm.
+func digestPathsAlgs(ctx context.Context, fsys fs.FS, algs map[string]func() hash.Hash, paths []string, each func(string, map[string]string, error) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opts := append(checksumAlgs(algs),
+		checksum.WithCtx(ctx),
+		checksum.WithGos(NumDigesters),
+	)
+	pipe, err := checksum.NewPipe(fsys, opts...)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer pipe.Close()
+		for _, p := range paths {
+			pipe.Add(p)
+		}
+	}()
+	var eachErr error
+	for job := range pipe.Out() {
+		if eachErr != nil {
+			continue // drain
+		}
+		var sums map[string]string
+		err := job.Err()
+		if err == nil {
+			sums, err = jobSums(job, algs)
+		}
+		if eachErr = each(job.Path(), sums, err); eachErr != nil {
+			cancel()
+		}
+	}
+	if eachErr != nil {
+		return eachErr
+	}
+	return ctx.Err()
+}
+
+// digestFileAlg returns the hex-encoded alg digest of the named file.
+func (root *objectRoot) digestFileAlg(name string, alg string) (string, error) {
+	newH, err := newHash(alg)
+	if err != nil {
+		return "", err
+	}
+	f, err := root.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := newH()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,99 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestFastIndex(t *testing.T) {
+	ctx := context.Background()
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.BuildFastIndex(ctx, `md5`); err == nil {
+		t.Error("expected an error for a non-fast algorithm")
+	}
+	idx, err := obj.BuildFastIndex(ctx, internal.CRC32C)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Entries) != 4 {
+		t.Fatalf("expected 4 index entries, got %d", len(idx.Entries))
+	}
+	// serialization round trip
+	data, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var idx2 internal.FastIndex
+	if err := json.Unmarshal(data, &idx2); err != nil {
+		t.Fatal(err)
+	}
+	report, err := obj.VerifyFast(ctx, idx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid() || report.Checked != 4 || len(report.Stale) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	// a stale index entry passes the full check
+	idx2.Entries[`v1/content/image.tiff`] = `00000000`
+	delete(idx2.Entries, `v1/content/empty.txt`)
+	// corrupt and missing content
+	fsys[`v1/content/foo/bar.xml`] = &fstest.MapFile{Data: []byte(`corrupt`)}
+	delete(fsys, `v2/content/foo/bar.xml`)
+	report, err = obj.VerifyFast(ctx, idx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid() {
+		t.Error("expected report to be invalid")
+	}
+	expect := internal.FastReport{
+		Checked: 4,
+		Corrupt: []string{`v1/content/foo/bar.xml`},
+		Missing: []string{`v2/content/foo/bar.xml`},
+		Stale:   []string{`v1/content/empty.txt`, `v1/content/image.tiff`},
+	}
+	if !reflect.DeepEqual(report, expect) {
+		t.Errorf("expected %+v, got %+v", expect, report)
+	}
+}
+
+// VerifyFast opens files with ctx, including files that are digested again
+// because they don't match the index.
+func TestFastIndexContext(t *testing.T) {
+	fsys := &ctxFS{MapFS: loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))}
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt64(&fsys.calls, 0)
+	idx := internal.FastIndex{Algorithm: internal.CRC32C, Entries: map[string]string{}}
+	report, err := obj.VerifyFast(context.Background(), idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Stale) != 4 {
+		t.Fatalf("expected 4 stale entries, got %+v", report)
+	}
+	// four fast checks and four full checks
+	if calls := atomic.LoadInt64(&fsys.calls); calls != 8 {
+		t.Errorf("expected 8 calls to OpenContext, got %d", calls)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := obj.VerifyFast(ctx, idx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
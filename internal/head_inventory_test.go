@@ -0,0 +1,44 @@
+package internal_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestRootInventoryMatchesHead(t *testing.T) {
+	// reformatted but equivalent root inventory
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	var reformatted bytes.Buffer
+	if err := json.Compact(&reformatted, fsys[`inventory.json`].Data); err != nil {
+		t.Fatal(err)
+	}
+	digest := sha512Hex(reformatted.Bytes())
+	fsys[`inventory.json`] = &fstest.MapFile{Data: reformatted.Bytes()}
+	fsys[`inventory.json.sha512`] = &fstest.MapFile{Data: []byte(digest + " inventory.json\n")}
+	result := internal.ValidateObject(fsys)
+	if result.Valid() {
+		t.Fatal("expected reformatted root inventory to be invalid")
+	}
+	err := result.Fatal()[0]
+	if !errors.Is(err, &internal.ErrE064) {
+		t.Errorf("expected E064, got %v", err)
+	}
+	if !strings.Contains(err.Error(), digest) {
+		t.Errorf("expected error to include the root inventory digest: %v", err)
+	}
+	// identical inventories with different sidecar formatting
+	fsys = loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	sidecar := strings.Replace(string(fsys[`inventory.json.sha512`].Data), " ", "  ", 1)
+	fsys[`inventory.json.sha512`] = &fstest.MapFile{Data: []byte(sidecar)}
+	result = internal.ValidateObject(fsys)
+	if result.Valid() || !errors.Is(result.Fatal()[0], &internal.ErrE064) {
+		t.Errorf("expected E064 for mismatched sidecars, got %v", result.Fatal())
+	}
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"time"
+)
+
+// Event types
+const (
+	EventVersionCreated = `version-created`
+	EventValidated      = `validated`
+)
+
+// Event is an entry in an object's event history.
+type Event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Version string    `json:"version,omitempty"`
+	User    *User     `json:"user,omitempty"`
+	Message string    `json:"message,omitempty"`
+	// FilesAdded, FilesRemoved, and FilesModified are the changes in
+	// logical paths from the previous version, for version-created events.
+	// Paths with different content in the two versions are only counted in
+	// FilesModified.
+	FilesAdded    int `json:"files_added,omitempty"`
+	FilesRemoved  int `json:"files_removed,omitempty"`
+	FilesModified int `json:"files_modified,omitempty"`
+	// BytesDelta is the change in the version's logical size from the
+	// previous version, if the version stats extension is present.
+	BytesDelta *int64 `json:"bytes_delta,omitempty"`
+	// Outcome is "valid" or "invalid" for validated events
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// EventSink receives events from operations on an object. See
+// WithEventSink.
+type EventSink interface {
+	Record(Event) error
+}
+
+// WithEventSink sets an EventSink that receives an event when validation
+// completes. Errors from the sink are ignored.
+func WithEventSink(sink EventSink) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.eventSink = sink
+	}
+}
+
+// History returns a version-created event for each version in the
+// inventory, in version order.
+func (obj *ObjectReader) History() []Event {
+	vnames := make([]string, 0, len(obj.inventory.Versions))
+	for v := range obj.inventory.Versions {
+		vnames = append(vnames, v)
+	}
+	sortVersions(vnames)
+	events := make([]Event, 0, len(vnames))
+	prev := map[string]string{}
+	var prevBytes int64
+	// the stats file is read once; if it can't be read, there are no byte
+	// deltas
+	statsFile, statsErr := obj.readVersionStats()
+	for _, v := range vnames {
+		ver := obj.inventory.Versions[v]
+		event := Event{
+			Type:    EventVersionCreated,
+			Time:    ver.Created,
+			Version: v,
+			Message: ver.Message,
+		}
+		if ver.User.Name != "" || ver.User.Address != "" {
+			user := ver.User
+			event.User = &user
+		}
+		paths, _ := ver.State.Paths()
+		for p, digest := range paths {
+			prevDigest, ok := prev[p]
+			switch {
+			case !ok:
+				event.FilesAdded++
+			case prevDigest != digest:
+				event.FilesModified++
+			}
+		}
+		for p := range prev {
+			if _, ok := paths[p]; !ok {
+				event.FilesRemoved++
+			}
+		}
+		if stats, ok := statsFile.Versions[v]; statsErr == nil && ok {
+			delta := stats.Bytes - prevBytes
+			event.BytesDelta = &delta
+			prevBytes = stats.Bytes
+		}
+		events = append(events, event)
+		prev = paths
+	}
+	return events
+}
+
+// recordValidation sends a validated event to the configured EventSink
+func (obj *ObjectReader) recordValidation(result ValidationResult) {
+	if obj.opts.eventSink == nil {
+		return
+	}
+	outcome := "valid"
+	if !result.Valid() {
+		outcome = "invalid"
+	}
+	obj.opts.eventSink.Record(Event{
+		Type:    EventValidated,
+		Time:    time.Now().UTC(),
+		Version: obj.inventory.Head,
+		Outcome: outcome,
+	})
+}
@@ -0,0 +1,99 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+type recordingSink struct {
+	events []internal.Event
+}
+
+func (s *recordingSink) Record(e internal.Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func TestHistory(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := obj.History()
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	expected := []struct {
+		version                  string
+		added, removed, modified int
+	}{
+		{`v1`, 3, 0, 0},
+		{`v2`, 1, 1, 1},
+		{`v3`, 1, 1, 0},
+	}
+	for i, exp := range expected {
+		e := events[i]
+		if e.Type != internal.EventVersionCreated || e.Version != exp.version {
+			t.Errorf("event %d: unexpected type or version: %s %s", i, e.Type, e.Version)
+		}
+		if e.FilesAdded != exp.added || e.FilesRemoved != exp.removed || e.FilesModified != exp.modified {
+			t.Errorf("%s: expected +%d/-%d/~%d, got +%d/-%d/~%d", e.Version, exp.added, exp.removed, exp.modified,
+				e.FilesAdded, e.FilesRemoved, e.FilesModified)
+		}
+		if e.User == nil || e.User.Name == "" || e.Message == "" || e.Time.IsZero() {
+			t.Errorf("%s: missing event metadata: %+v", e.Version, e)
+		}
+		if e.BytesDelta != nil {
+			t.Errorf("%s: expected no byte delta without version stats", e.Version)
+		}
+	}
+	// byte deltas from version stats
+	stats, err := obj.RecomputeVersionStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys[internal.VersionStatsPath] = &fstest.MapFile{Data: data}
+	events = obj.History()
+	if events[1].BytesDelta == nil || *events[1].BytesDelta != 272-2293 {
+		t.Errorf("unexpected v2 byte delta: %v", events[1].BytesDelta)
+	}
+	if _, err := json.Marshal(events); err != nil {
+		t.Fatal(err)
+	}
+	// the stats file is read once
+	ops := &opsFS{FS: fsys, opens: map[string]int{}, stats: map[string]int{}}
+	obj, err = internal.NewObjectReader(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj.History()
+	if n := ops.opens[internal.VersionStatsPath]; n != 1 {
+		t.Errorf("expected version stats to be read once, got %d", n)
+	}
+}
+
+func TestEventSink(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	sink := &recordingSink{}
+	result := internal.ValidateObject(fsys, internal.WithEventSink(sink))
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(sink.events))
+	}
+	e := sink.events[0]
+	if e.Type != internal.EventValidated || e.Outcome != `valid` || e.Version != `v3` {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
@@ -15,14 +15,20 @@
 package internal
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"time"
 )
 
 //var invSidecarRexp = regexp.MustCompile(`inventory\.json\.(\w+)`)
 
-// Inventory represents contents of an OCFL Object's inventory.json file
+// Inventory represents contents of an OCFL Object's inventory.json file.
+// Inventory and DigestMap methods don't modify their receivers (except
+// DigestMap.Add) and are safe for concurrent use.
 type Inventory struct {
 	ID               string               `json:"id"`
 	Type             string               `json:"type"`
@@ -33,6 +39,15 @@ type Inventory struct {
 	Versions         map[string]*Version  `json:"versions"`
 	Fixity           map[string]DigestMap `json:"fixity,omitempty"`
 	digest           []byte               // digest of inventory file
+	bom              bool                 // inventory file began with a BOM
+	raw              []byte               // inventory file contents
+	lowMemory        bool                 // read in low-memory mode
+	noContentDir     bool                 // contentDirectory key was absent
+	// content directories of versions that differ from ContentDirectory
+	versionContentDirs map[string]string
+	// version name -> *PathIndex for the version's state, for inventories
+	// that were read from JSON (see stateIndex)
+	stateIndexes *sync.Map
 }
 
 // Version represent a version entryin inventory.json
@@ -43,6 +58,25 @@ type Version struct {
 	User    User      `json:"user,omitempty"`
 }
 
+// StateTree returns the directory tree implied by the version's logical
+// paths. Files in the tree have their digests as values. It returns a
+// *PathConflictErr if a logical path is used as both a file and a
+// directory.
+func (v *Version) StateTree() (*PathTree, error) {
+	if err := v.State.Valid(); err != nil {
+		return nil, err
+	}
+	tree := &PathTree{}
+	for digest, paths := range v.State {
+		for _, p := range paths {
+			if err := tree.Add(p, digest); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tree, nil
+}
+
 // User represent a Version's user entry
 type User struct {
 	Name    string `json:"name"`
@@ -57,25 +91,34 @@ func inventoryDefaults() *Inventory {
 
 func ReadInventory(file io.Reader) (*Inventory, error) {
 	inv := inventoryDefaults()
-	decoder := json.NewDecoder(file)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
 
 	// The OCFL spec (v1.0) allows uknown fields in some places (in
 	// Versions, for examples). Using DisallowUnknownFields() would
 	// invalidate some valid objects. Best to leave this disabled.
 	// decoder.DisallowUnknownFields()
 
-	err := decoder.Decode(inv)
+	err = decoder.Decode(inv)
 	if err != nil {
 		switch err := err.(type) {
 		case *time.ParseError:
-			return nil, asValidationErr(err, &ErrE049)
+			return nil, asValidationErr(createdParseErr(data, err), &ErrE049)
 		case *json.UnmarshalTypeError:
 			if err.Field == "head" {
 				return nil, asValidationErr(err, &ErrE040)
 			}
-			if err.Field == `versions.message` {
+			// Field may or may not include the version name depending on
+			// the version of encoding/json (e.g., versions.v1.message)
+			if versionField(err.Field, `message`) {
 				return nil, asValidationErr(err, &ErrE094)
 			}
+			if versionField(err.Field, `created`) {
+				return nil, asValidationErr(err, &ErrE049)
+			}
 			// Todo other special cases?
 		}
 		return nil, asValidationErr(err, &ErrE033)
@@ -83,6 +126,39 @@ func ReadInventory(file io.Reader) (*Inventory, error) {
 	return inv, nil
 }
 
+// createdParseErr adds the name of the version with an invalid created
+// timestamp to err.
+func createdParseErr(data []byte, err *time.ParseError) error {
+	var aux struct {
+		Versions map[string]struct {
+			Created json.RawMessage `json:"created"`
+		} `json:"versions"`
+	}
+	if json.Unmarshal(data, &aux) != nil {
+		return err
+	}
+	names := make([]string, 0, len(aux.Versions))
+	for name := range aux.Versions {
+		names = append(names, name)
+	}
+	sortVersions(names)
+	for _, name := range names {
+		var created string
+		if json.Unmarshal(aux.Versions[name].Created, &created) != nil {
+			continue
+		}
+		if _, parseErr := time.Parse(time.RFC3339, created); parseErr != nil {
+			return fmt.Errorf("version %s created is not an RFC3339 timestamp: %w", name, err)
+		}
+	}
+	return err
+}
+
+// versionField returns true if field is the named field in a version block
+func versionField(field string, name string) bool {
+	return strings.HasPrefix(field, `versions.`) && strings.HasSuffix(field, `.`+name)
+}
+
 // func ReadInventoryChecksum(file io.Reader, alg string) (*Inventory, error) {
 // 	newH, err := newHash(alg)
 // 	if err != nil {
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// WithoutInventoryCache configures NewObjectReader not to retain the root
+// inventory's bytes after parsing it. InventoryBytes and InventoryDigest
+// read the inventory file again instead.
+func WithoutInventoryCache() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.noInventoryCache = true
+	}
+}
+
+// InventoryBytes returns the exact contents of the inventory file the
+// ObjectReader was opened with: the root inventory or, if the ObjectReader
+// is degraded, the head version's inventory. If the ObjectReader was
+// created with WithoutInventoryCache, the file is read again and may have
+// changed since the ObjectReader was opened.
+func (obj *ObjectReader) InventoryBytes() ([]byte, error) {
+	if obj.inventory.raw != nil {
+		return append([]byte{}, obj.inventory.raw...), nil
+	}
+	return fs.ReadFile(obj.root, path.Join(obj.inventoryDir(), inventoryFile))
+}
+
+// InventoryDigest returns the inventory's digest algorithm and the digest
+// of the bytes returned by InventoryBytes. If the inventory sidecar exists
+// and doesn't match the digest, the algorithm and digest are returned with
+// an E034 validation error.
+func (obj *ObjectReader) InventoryDigest() (string, string, error) {
+	alg := obj.inventory.DigestAlgorithm
+	raw, err := obj.InventoryBytes()
+	if err != nil {
+		return alg, "", err
+	}
+	newH, err := newHash(alg)
+	if err != nil {
+		return alg, "", err
+	}
+	h := newH()
+	h.Write(raw)
+	digest := hex.EncodeToString(h.Sum(nil))
+	sidecar, err := obj.root.readInventorySidecar(obj.inventoryDir(), alg)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return alg, digest, nil
+		}
+		return alg, digest, err
+	}
+	if sidecar != digest {
+		err := fmt.Errorf("inventory digest doesn't match sidecar: %s", path.Join(obj.inventoryDir(), obj.inventory.SidecarFile()))
+		return alg, digest, asValidationErr(err, &ErrE034)
+	}
+	return alg, digest, nil
+}
+
+// inventoryDir is the directory of the inventory file the ObjectReader was
+// opened with.
+func (obj *ObjectReader) inventoryDir() string {
+	if obj.Degraded() {
+		return obj.inventory.Head
+	}
+	return `.`
+}
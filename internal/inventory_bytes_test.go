@@ -0,0 +1,61 @@
+package internal_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestInventoryBytes(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	original := fsys[`inventory.json`].Data
+	sidecar := strings.Fields(string(fsys[`inventory.json.sha512`].Data))[0]
+	for name, opts := range map[string][]internal.ObjectOption{
+		`cached`:   nil,
+		`uncached`: {internal.WithoutInventoryCache()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			obj, err := internal.NewObjectReader(fsys, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := obj.InventoryBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(raw, original) {
+				t.Error("InventoryBytes doesn't match the inventory file")
+			}
+			alg, digest, err := obj.InventoryDigest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if alg != `sha512` || digest != sidecar {
+				t.Errorf("unexpected digest: %s %s", alg, digest)
+			}
+		})
+	}
+	// sidecar mismatch
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys[`inventory.json.sha512`] = &fstest.MapFile{Data: []byte(strings.Repeat("0", 128) + " inventory.json\n")}
+	_, digest, err := obj.InventoryDigest()
+	if !errors.Is(err, &internal.ErrE034) {
+		t.Errorf("expected E034, got %v", err)
+	}
+	if digest != sidecar {
+		t.Errorf("expected digest to be returned with mismatch error, got %s", digest)
+	}
+	// sidecar missing
+	delete(fsys, `inventory.json.sha512`)
+	if _, _, err := obj.InventoryDigest(); err != nil {
+		t.Errorf("unexpected error without sidecar: %v", err)
+	}
+}
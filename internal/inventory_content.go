@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrNotContentPath indicates a path that is not a content path in the
+// inventory: it isn't in the form `{version}/{contentDirectory}/{path}`
+var ErrNotContentPath = errors.New(`not a content path`)
+
+// ContentEntry represents a manifest entry and the logical paths that
+// reference it.
+type ContentEntry struct {
+	Digest      string
+	ContentPath string
+	// LogicalPaths maps version names to the logical paths in the version
+	// state that reference the digest.
+	LogicalPaths map[string][]string
+}
+
+// contentDirectory returns the content directory name, using the default if
+// it isn't set.
+func (inv *Inventory) contentDirectory() string {
+	if inv.ContentDirectory == "" {
+		return contentDir
+	}
+	return inv.ContentDirectory
+}
+
+// VersionOfContentPath returns the name of the version that introduced the
+// content path p. An error is returned if p is not in the form
+// `{version}/{contentDirectory}/{path}` for a version in the inventory.
+func (inv *Inventory) VersionOfContentPath(p string) (string, error) {
+	ref, err := ParseContentRef(p)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := inv.Versions[ref.Version]; !ok {
+		return "", fmt.Errorf("%w: %s: no version %s", ErrNotContentPath, p, ref.Version)
+	}
+	if dir := inv.versionContentDir(ref.Version); ref.ContentDir != dir {
+		return "", fmt.Errorf("%w: %s: content directory is %s", ErrNotContentPath, p, dir)
+	}
+	return ref.Version, nil
+}
+
+// ContentIntroducedIn returns ContentEntries for each manifest path in the
+// content directory of version vname, sorted by content path. Each entry
+// includes the logical paths, across all versions, that reference the
+// digest.
+func (inv *Inventory) ContentIntroducedIn(vname string) ([]ContentEntry, error) {
+	if _, ok := inv.Versions[vname]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrVersionNotFound, vname)
+	}
+	var entries []ContentEntry
+	for digest, paths := range inv.Manifest {
+		for _, p := range paths {
+			v, err := inv.VersionOfContentPath(p)
+			if err != nil {
+				return nil, err
+			}
+			if v != vname {
+				continue
+			}
+			entries = append(entries, ContentEntry{
+				Digest:       digest,
+				ContentPath:  p,
+				LogicalPaths: inv.logicalPaths(digest),
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ContentPath < entries[j].ContentPath
+	})
+	return entries, nil
+}
+
+// logicalPaths returns map of version names to logical paths for digest.
+// Digests are compared case-insensitively.
+func (inv *Inventory) logicalPaths(digest string) map[string][]string {
+	lpaths := make(map[string][]string)
+	for vname, ver := range inv.Versions {
+		for d, paths := range ver.State {
+			if !strings.EqualFold(d, digest) {
+				continue
+			}
+			lpaths[vname] = append(lpaths[vname], paths...)
+		}
+		sort.Strings(lpaths[vname])
+	}
+	for vname, paths := range lpaths {
+		if len(paths) == 0 {
+			delete(lpaths, vname)
+		}
+	}
+	return lpaths
+}
+
+// FixityFor returns the fixity digests for content path p, keyed by
+// algorithm. Algorithms that aren't supported are included.
+func (inv *Inventory) FixityFor(p string) map[string]string {
+	fixity := map[string]string{}
+	for alg, dm := range inv.Fixity {
+		if digest, ok := dm.PathDigest(p); ok {
+			fixity[alg] = digest
+		}
+	}
+	return fixity
+}
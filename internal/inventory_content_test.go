@@ -0,0 +1,105 @@
+package internal_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func readFixtureInventory(t *testing.T, dir string) *internal.Inventory {
+	t.Helper()
+	file, err := os.Open(filepath.Join(dir, `inventory.json`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	inv, err := internal.ReadInventory(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inv
+}
+
+func TestContentIntroducedIn(t *testing.T) {
+	inv := readFixtureInventory(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	entries, err := inv.ContentIntroducedIn(`v1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries for v1, got %d", len(entries))
+	}
+	empty := entries[0]
+	if empty.ContentPath != `v1/content/empty.txt` {
+		t.Errorf("expected v1/content/empty.txt, got %s", empty.ContentPath)
+	}
+	expected := map[string][]string{
+		`v1`: {`empty.txt`},
+		`v2`: {`empty.txt`, `empty2.txt`},
+		`v3`: {`empty2.txt`},
+	}
+	if !reflect.DeepEqual(empty.LogicalPaths, expected) {
+		t.Errorf("expected logical paths %v, got %v", expected, empty.LogicalPaths)
+	}
+	entries, err = inv.ContentIntroducedIn(`v3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no content introduced in v3, got %d", len(entries))
+	}
+	_, err = inv.ContentIntroducedIn(`v4`)
+	if !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+
+	// non-standard content directory
+	inv = readFixtureInventory(t, filepath.Join(goodObjPath, `minimal_content_dir_called_stuff`))
+	entries, err = inv.ContentIntroducedIn(`v1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ContentPath != `v1/stuff/a_file.txt` {
+		t.Errorf("unexpected entries: %v", entries)
+	}
+
+	// padded version names
+	inv = readFixtureInventory(t, filepath.Join(warnObjPath, `W001_zero_padded_versions`))
+	entries, err = inv.ContentIntroducedIn(`v002`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ContentPath != `v002/content/a_file.txt` {
+		t.Errorf("unexpected entries: %v", entries)
+	}
+}
+
+func TestVersionOfContentPath(t *testing.T) {
+	inv := readFixtureInventory(t, filepath.Join(goodObjPath, `minimal_content_dir_called_stuff`))
+	table := map[string]bool{
+		`v1/stuff/a_file.txt`:   true,
+		`v1/stuff/a/b/c.txt`:    true,
+		`v1/content/a_file.txt`: false,
+		`v2/stuff/a_file.txt`:   false,
+		`v1/a_file.txt`:         false,
+		`stuff/a_file.txt`:      false,
+		`../v1/stuff/a.txt`:     false,
+		``:                      false,
+	}
+	for p, valid := range table {
+		v, err := inv.VersionOfContentPath(p)
+		if valid && err != nil {
+			t.Errorf("VersionOfContentPath(%q): unexpected error: %v", p, err)
+		}
+		if valid && v != `v1` {
+			t.Errorf("VersionOfContentPath(%q): expected v1, got %s", p, v)
+		}
+		if !valid && err == nil {
+			t.Errorf("VersionOfContentPath(%q): expected an error", p)
+		}
+	}
+}
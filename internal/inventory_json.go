@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// inventoryJSON is the JSON representation of an Inventory. Optional keys
+// are pointers so their presence is preserved: an inventory without a
+// contentDirectory or fixity key is marshaled without it, and an empty
+// fixity block is marshaled as empty.
+type inventoryJSON struct {
+	ID               string                `json:"id"`
+	Type             string                `json:"type"`
+	DigestAlgorithm  string                `json:"digestAlgorithm"`
+	Head             string                `json:"head"`
+	ContentDirectory *string               `json:"contentDirectory,omitempty"`
+	Manifest         DigestMap             `json:"manifest"`
+	Versions         map[string]*Version   `json:"versions"`
+	Fixity           *map[string]DigestMap `json:"fixity,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Inventory. If the
+// contentDirectory key is absent, ContentDirectory is set to the default.
+func (inv *Inventory) UnmarshalJSON(b []byte) error {
+	var aux inventoryJSON
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	inv.ID = aux.ID
+	inv.Type = aux.Type
+	inv.DigestAlgorithm = aux.DigestAlgorithm
+	inv.Head = aux.Head
+	inv.Manifest = aux.Manifest
+	inv.Versions = aux.Versions
+	inv.stateIndexes = &sync.Map{}
+	inv.ContentDirectory = contentDir
+	inv.noContentDir = aux.ContentDirectory == nil
+	if aux.ContentDirectory != nil {
+		inv.ContentDirectory = *aux.ContentDirectory
+	}
+	inv.Fixity = nil
+	if aux.Fixity != nil {
+		inv.Fixity = *aux.Fixity
+		if inv.Fixity == nil {
+			inv.Fixity = map[string]DigestMap{}
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for Inventory. The contentDirectory
+// key is omitted if ContentDirectory is empty, or if it was absent from the
+// inventory that was read and is still the default. The fixity key is
+// omitted if Fixity is nil.
+func (inv Inventory) MarshalJSON() ([]byte, error) {
+	aux := inventoryJSON{
+		ID:              inv.ID,
+		Type:            inv.Type,
+		DigestAlgorithm: inv.DigestAlgorithm,
+		Head:            inv.Head,
+		Manifest:        inv.Manifest,
+		Versions:        inv.Versions,
+	}
+	if inv.ContentDirectory != "" && !(inv.noContentDir && inv.ContentDirectory == contentDir) {
+		aux.ContentDirectory = &inv.ContentDirectory
+	}
+	if inv.Fixity != nil {
+		aux.Fixity = &inv.Fixity
+	}
+	return json.Marshal(aux)
+}
+
+// MarshalJSON implements json.Marshaler for Version. The user key is
+// omitted if User is the zero value.
+func (v Version) MarshalJSON() ([]byte, error) {
+	type version Version // without MarshalJSON
+	aux := struct {
+		version
+		User *User `json:"user,omitempty"`
+	}{version: version(v)}
+	if v.User != (User{}) {
+		aux.User = &v.User
+	}
+	return json.Marshal(aux)
+}
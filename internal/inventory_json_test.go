@@ -0,0 +1,82 @@
+package internal_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// canonicalJSON re-encodes data with sorted keys and no whitespace
+func canonicalJSON(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var val interface{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// Inventories in good and warn fixtures should be unchanged, apart from
+// formatting, by reading and re-marshaling them.
+func TestInventoryRoundTrip(t *testing.T) {
+	for _, dir := range []string{goodObjPath, warnObjPath} {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.Name() != `inventory.json` {
+				return err
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			inv, err := internal.ReadInventory(bytes.NewReader(data))
+			if err != nil {
+				t.Errorf("%s: %v", p, err)
+				return nil
+			}
+			out, err := json.Marshal(inv)
+			if err != nil {
+				return err
+			}
+			if expected, got := canonicalJSON(t, data), canonicalJSON(t, out); !bytes.Equal(expected, got) {
+				t.Errorf("%s: round trip changed inventory:\n%s\n%s", p, expected, got)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInventoryOptionalKeys(t *testing.T) {
+	base := `{"id":"x","type":"https://ocfl.io/1.0/spec/#inventory","digestAlgorithm":"sha512","head":"v1","manifest":{},"versions":{}`
+	table := map[string]string{
+		`absent`:                  base + `}`,
+		`empty fixity`:            base + `,"fixity":{}}`,
+		`empty fixity alg`:        base + `,"fixity":{"md5":{}}}`,
+		`default content dir`:     base + `,"contentDirectory":"content"}`,
+		`non-default content dir`: base + `,"contentDirectory":"data"}`,
+	}
+	for name, data := range table {
+		inv, err := internal.ReadInventory(bytes.NewReader([]byte(data)))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		out, err := json.Marshal(inv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := canonicalJSON(t, []byte(data)), canonicalJSON(t, out); !bytes.Equal(expected, got) {
+			t.Errorf("%s: expected %s, got %s", name, expected, got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// DefaultMaxInventorySize is the default InventoryLimits.MaxSize
+const DefaultMaxInventorySize int64 = 1 << 30 // 1 GiB
+
+// Inventory limits reported by InventoryLimitErr
+const (
+	LimitInventorySize = `size`
+	LimitPathLength    = `path length`
+	LimitPaths         = `path count`
+)
+
+// ErrInventoryTooLarge indicates an inventory exceeded one of the
+// InventoryLimits. It is wrapped by *InventoryLimitErr.
+var ErrInventoryTooLarge = errors.New(`inventory exceeds limit`)
+
+// InventoryLimits protect against inventories that would use excessive
+// memory to read. Only MaxSize is set by default: a zero MaxSize uses
+// DefaultMaxInventorySize. Other limits are disabled if they are zero.
+// Negative values disable a limit.
+type InventoryLimits struct {
+	// MaxSize is the maximum inventory file size in bytes. The default is
+	// DefaultMaxInventorySize.
+	MaxSize int64
+	// MaxPathLength is the maximum length in bytes of content paths and
+	// logical paths. There is no limit by default: the OCFL spec doesn't
+	// limit path length.
+	MaxPathLength int
+	// MaxPaths is the maximum number of paths in the manifest, version
+	// states, and fixity blocks combined. There is no limit by default.
+	MaxPaths int
+	// LowMemorySize is the inventory size in bytes above which the
+	// inventory's bytes aren't retained (see WithoutInventoryCache) and JSON
+	// schema validation is skipped during validation, with a warning. The
+	// inventory is still read and parsed in full, so this only saves the
+	// memory used by schema validation and the retained bytes. It is
+	// disabled by default.
+	LowMemorySize int64
+}
+
+// WithInventoryLimits sets limits on inventories read by NewObjectReader and
+// during validation. Inventories that exceed them can't be opened or fail
+// validation with an error wrapping ErrInventoryTooLarge.
+func WithInventoryLimits(limits InventoryLimits) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.limits = limits
+	}
+}
+
+// InventoryLimitErr is returned when an inventory exceeds one of the
+// InventoryLimits. It wraps ErrInventoryTooLarge.
+type InventoryLimitErr struct {
+	Name  string // inventory file
+	Limit string // LimitInventorySize, LimitPathLength, or LimitPaths
+	Max   int64  // the limit's value
+	Path  string // the path that exceeded LimitPathLength, truncated
+}
+
+func (e *InventoryLimitErr) Error() string {
+	msg := fmt.Sprintf("%s: inventory exceeds %s limit (%d)", e.Name, e.Limit, e.Max)
+	if e.Path != "" {
+		msg += ": " + strconv.Quote(e.Path)
+	}
+	return msg
+}
+
+func (e *InventoryLimitErr) Unwrap() error {
+	return ErrInventoryTooLarge
+}
+
+// withDefaults returns limits with the default MaxSize if it is zero.
+func (limits InventoryLimits) withDefaults() InventoryLimits {
+	if limits.MaxSize == 0 {
+		limits.MaxSize = DefaultMaxInventorySize
+	}
+	return limits
+}
+
+// lowMemory returns true if an inventory of size bytes should be read in
+// low-memory mode.
+func (limits InventoryLimits) lowMemory(size int) bool {
+	return limits.LowMemorySize > 0 && int64(size) > limits.LowMemorySize
+}
+
+// readAll reads the inventory file name from r, without reading more than
+// MaxSize+1 bytes.
+func (limits InventoryLimits) readAll(r io.Reader, name string) ([]byte, error) {
+	if limits.MaxSize < 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limits.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limits.MaxSize {
+		return nil, &InventoryLimitErr{Name: name, Limit: LimitInventorySize, Max: limits.MaxSize}
+	}
+	return data, nil
+}
+
+// checkPaths scans the inventory JSON for paths that exceed MaxPathLength
+// or MaxPaths, without unmarshaling it. In inventories, every string in an
+// array is a path. Syntax errors are ignored: they are reported when the
+// inventory is parsed.
+func (limits InventoryLimits) checkPaths(data []byte, name string) error {
+	if limits.MaxPathLength <= 0 && limits.MaxPaths <= 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var arrays, count int
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		switch tok := tok.(type) {
+		case json.Delim:
+			switch tok {
+			case '[':
+				arrays++
+			case ']':
+				arrays--
+			}
+		case string:
+			if arrays == 0 {
+				continue
+			}
+			count++
+			if limits.MaxPaths > 0 && count > limits.MaxPaths {
+				return &InventoryLimitErr{Name: name, Limit: LimitPaths, Max: int64(limits.MaxPaths)}
+			}
+			if limits.MaxPathLength > 0 && len(tok) > limits.MaxPathLength {
+				p := tok
+				if len(p) > 64 {
+					p = p[:64] + "..."
+				}
+				return &InventoryLimitErr{Name: name, Limit: LimitPathLength, Max: int64(limits.MaxPathLength), Path: p}
+			}
+		}
+	}
+}
+
+// lowMemoryErr is the warning for an inventory read in low-memory mode.
+func lowMemoryErr(name string) error {
+	return fmt.Errorf("%s: inventory exceeds low-memory size: schema validation skipped", name)
+}
@@ -0,0 +1,156 @@
+package internal_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// inflatedFS is an object whose root inventory never ends
+type inflatedFS struct {
+	fstest.MapFS
+}
+
+func (fsys inflatedFS) Open(name string) (fs.File, error) {
+	if name == `inventory.json` {
+		return &endlessFile{prefix: []byte(`{"id": "endless", "manifest": {`)}, nil
+	}
+	return fsys.MapFS.Open(name)
+}
+
+// endlessFile reads prefix followed by endless whitespace
+type endlessFile struct {
+	prefix []byte
+}
+
+func (f *endlessFile) Read(p []byte) (int, error) {
+	n := copy(p, f.prefix)
+	f.prefix = f.prefix[n:]
+	for i := n; i < len(p); i++ {
+		p[i] = ' '
+	}
+	return len(p), nil
+}
+
+func (f *endlessFile) Stat() (fs.FileInfo, error) {
+	return nil, errors.New("endless file has no size")
+}
+
+func (f *endlessFile) Close() error { return nil }
+
+func limitErr(t *testing.T, err error, limit string) {
+	t.Helper()
+	if !errors.Is(err, internal.ErrInventoryTooLarge) {
+		t.Fatalf("expected ErrInventoryTooLarge, got %v", err)
+	}
+	var lerr *internal.InventoryLimitErr
+	if !errors.As(err, &lerr) {
+		t.Fatalf("expected *InventoryLimitErr, got %T", err)
+	}
+	if lerr.Limit != limit {
+		t.Errorf("expected %s limit, got %s", limit, lerr.Limit)
+	}
+}
+
+func TestInventoryLimitSize(t *testing.T) {
+	fsys := inflatedFS{loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))}
+	limits := internal.InventoryLimits{MaxSize: 1 << 20}
+	_, err := internal.NewObjectReader(fsys, internal.WithInventoryLimits(limits))
+	limitErr(t, err, internal.LimitInventorySize)
+	result := internal.ValidateObject(fsys, internal.WithInventoryLimits(limits))
+	if result.Valid() {
+		t.Fatal("expected validation to fail")
+	}
+}
+
+func TestInventoryLimitPaths(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	limits := internal.InventoryLimits{MaxPaths: 5}
+	_, err := internal.NewObjectReader(fsys, internal.WithInventoryLimits(limits))
+	limitErr(t, err, internal.LimitPaths)
+	result := internal.ValidateObject(fsys, internal.WithInventoryLimits(limits))
+	if result.Valid() {
+		t.Fatal("expected validation to fail")
+	}
+	var found bool
+	for _, err := range result.Fatal() {
+		if errors.Is(err, internal.ErrInventoryTooLarge) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected ErrInventoryTooLarge, got %v", result.Fatal())
+	}
+	// the default is no limit
+	if _, err := internal.NewObjectReader(fsys); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInventoryLimitPathLength(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	// a deeply nested logical path
+	long := strings.Repeat("dir/", 1024) + "file.txt"
+	v3 := inv["versions"].(map[string]interface{})["v3"].(map[string]interface{})
+	for digest, paths := range v3["state"].(map[string]interface{}) {
+		v3["state"].(map[string]interface{})[digest] = append(paths.([]interface{}), long)
+		break
+	}
+	setInventory(t, fsys, `.`, inv)
+	limits := internal.InventoryLimits{MaxPathLength: 4096}
+	_, err := internal.NewObjectReader(fsys, internal.WithInventoryLimits(limits))
+	limitErr(t, err, internal.LimitPathLength)
+	// the default is no limit
+	if _, err := internal.NewObjectReader(fsys); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInventoryLowMemory(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	limits := internal.InventoryLimits{LowMemorySize: 100}
+	obj, err := internal.NewObjectReader(fsys, internal.WithInventoryLimits(limits))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// read again from fsys
+	raw, err := obj.InventoryBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw, fsys[`inventory.json`].Data) {
+		t.Error("InventoryBytes didn't return the inventory file")
+	}
+	result := internal.ValidateObject(fsys, internal.WithInventoryLimits(limits))
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	var warnings int
+	for _, err := range result.Warning() {
+		if strings.Contains(err.Error(), "schema validation skipped") {
+			warnings++
+		}
+	}
+	// root and three version inventories
+	if warnings != 4 {
+		t.Errorf("expected 4 low-memory warnings, got %v", result.Warning())
+	}
+	// disabled by default
+	result = internal.ValidateObject(fsys)
+	for _, err := range result.Warning() {
+		if strings.Contains(err.Error(), "schema validation skipped") {
+			t.Errorf("unexpected low-memory warning: %v", err)
+		}
+	}
+}
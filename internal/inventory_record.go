@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// InventoryRecord describes an inventory file in the object root or in a
+// version directory, along with its sidecar.
+type InventoryRecord struct {
+	// Version is the version directory with the inventory. It is empty for
+	// the root inventory.
+	Version string
+	// Missing is true if the version directory has no inventory. Other
+	// fields are not set.
+	Missing bool
+	// Inventory is the parsed inventory
+	Inventory *Inventory
+	// Digest is the digest of the inventory file, using the inventory's
+	// digest algorithm.
+	Digest string
+	// Sidecar is the digest recorded in the inventory's sidecar file.
+	Sidecar string
+	// SidecarErr is set if the sidecar could not be read.
+	SidecarErr error
+}
+
+// Match returns true if the inventory's digest matches its sidecar
+func (rec InventoryRecord) Match() bool {
+	return !rec.Missing && rec.Sidecar != "" && strings.EqualFold(rec.Digest, rec.Sidecar)
+}
+
+// Inventories returns InventoryRecords for the root inventory followed by
+// the inventory in each version directory, in version order.
+func (obj *ObjectReader) Inventories(ctx context.Context) ([]InventoryRecord, error) {
+	vnames := obj.inventory.VersionDirs()
+	sortVersions(vnames)
+	records := make([]InventoryRecord, 0, len(vnames)+1)
+	for _, vname := range append([]string{""}, vnames...) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rec, err := obj.InventoryAt(vname)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// InventoryAt returns the InventoryRecord for the inventory in version
+// directory vname, or the root inventory if vname is empty.
+func (obj *ObjectReader) InventoryAt(vname string) (InventoryRecord, error) {
+	dir := `.`
+	if vname != "" {
+		if _, ok := obj.inventory.Versions[vname]; !ok {
+			return InventoryRecord{}, fmt.Errorf("%w: %s", ErrVersionNotFound, vname)
+		}
+		dir = vname
+	}
+	rec, err := obj.root.readInventoryRecord(dir)
+	rec.Version = vname
+	return rec, err
+}
+
+// readInventoryRecord reads the inventory and sidecar in dir without
+// validating them.
+func (root *objectRoot) readInventoryRecord(dir string) (InventoryRecord, error) {
+	var rec InventoryRecord
+	file, err := root.Open(path.Join(dir, inventoryFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			rec.Missing = true
+			return rec, nil
+		}
+		return rec, err
+	}
+	defer file.Close()
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		return rec, err
+	}
+	rec.Inventory, err = ReadInventory(bytes.NewReader(raw))
+	if err != nil {
+		return rec, err
+	}
+	newH, err := newHash(rec.Inventory.DigestAlgorithm)
+	if err != nil {
+		return rec, err
+	}
+	h := newH()
+	h.Write(raw)
+	rec.Digest = hex.EncodeToString(h.Sum(nil))
+	rec.Inventory.digest = h.Sum(nil)
+	rec.Sidecar, rec.SidecarErr = root.readInventorySidecar(dir, rec.Inventory.DigestAlgorithm)
+	return rec, nil
+}
@@ -0,0 +1,79 @@
+package internal_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestInventories(t *testing.T) {
+	ctx := context.Background()
+	obj, err := internal.NewObjectReader(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := obj.Inventories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"", "v1", "v2", "v3"}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %d", len(expected), len(records))
+	}
+	for i, rec := range records {
+		if rec.Version != expected[i] {
+			t.Errorf("expected record %d to be for %q, got %q", i, expected[i], rec.Version)
+		}
+		if !rec.Match() {
+			t.Errorf("expected inventory %q digest to match sidecar", rec.Version)
+		}
+	}
+	if records[0].Digest != records[3].Digest {
+		t.Error("expected root and head inventory digests to match")
+	}
+	if records[1].Inventory.Head != `v1` {
+		t.Errorf("expected v1 inventory head to be v1, got %s", records[1].Inventory.Head)
+	}
+
+	// missing version inventory is recorded, not an error
+	obj, err = internal.NewObjectReader(os.DirFS(filepath.Join(warnObjPath, `W010_no_version_inventory`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err = obj.Inventories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing int
+	for _, rec := range records {
+		if rec.Missing {
+			missing++
+		}
+	}
+	if missing == 0 {
+		t.Error("expected a missing version inventory")
+	}
+
+	// modified version inventory doesn't match sidecar
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	inv := append(fsys[`v2/inventory.json`].Data, '\n')
+	fsys[`v2/inventory.json`] = &fstest.MapFile{Data: inv}
+	obj, err = internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := obj.InventoryAt(`v2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Match() {
+		t.Error("expected v2 inventory not to match sidecar")
+	}
+	if _, err := obj.InventoryAt(`v9`); err == nil {
+		t.Error("expected an error for missing version")
+	}
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// StringInvalidErr is a string in an inventory that isn't valid UTF-8 or
+// that contains control characters.
+type StringInvalidErr struct {
+	Field  string // where the string appears, e.g., "v1 message"
+	Value  string
+	Reason string
+}
+
+func (e *StringInvalidErr) Error() string {
+	return fmt.Sprintf("%s %s: %q", e.Field, e.Reason, e.Value)
+}
+
+// hasControlChar returns true if s includes a C0 control character.
+func hasControlChar(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 {
+			return true
+		}
+	}
+	return false
+}
+
+// validUTF8 returns a *StringInvalidErr if the inventory's JSON isn't valid
+// UTF-8. The error's value is the JSON string with the invalid bytes. JSON
+// decoding replaces invalid bytes, so this must be checked before the
+// inventory is decoded.
+func validUTF8(raw []byte) error {
+	if utf8.Valid(raw) {
+		return nil
+	}
+	i := 0
+	for i < len(raw) {
+		r, size := utf8.DecodeRune(raw[i:])
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+		i += size
+	}
+	// the JSON string including the invalid byte
+	start := bytes.LastIndexByte(raw[:i], '"') + 1
+	end := bytes.IndexByte(raw[i:], '"')
+	if end < 0 {
+		end = len(raw)
+	} else {
+		end += i
+	}
+	return &StringInvalidErr{
+		Field:  fmt.Sprintf("inventory string at byte %d", i),
+		Value:  string(raw[start:end]),
+		Reason: "is not valid UTF-8",
+	}
+}
+
+// stringWarnings returns errors for version messages and user names and
+// addresses that include control characters. Logical and content paths with
+// control characters are invalid (see DigestMap.Valid).
+func (inv *Inventory) stringWarnings() []error {
+	var errs []error
+	check := func(field, val string) {
+		if hasControlChar(val) {
+			errs = append(errs, &StringInvalidErr{
+				Field:  field,
+				Value:  val,
+				Reason: "includes control characters",
+			})
+		}
+	}
+	vnames := inv.VersionDirs()
+	sortVersions(vnames)
+	for _, vname := range vnames {
+		v := inv.Versions[vname]
+		if v == nil {
+			continue
+		}
+		check(vname+" message", v.Message)
+		check(vname+" user name", v.User.Name)
+		check(vname+" user address", v.User.Address)
+	}
+	return errs
+}
@@ -0,0 +1,86 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestInventoryStringsInvalid(t *testing.T) {
+	table := map[string]string{
+		`E033_latin1_logical_path`: `"caf\xe9.txt"`,
+		`E099_tab_in_logical_path`: `"a\tfile.txt"`,
+	}
+	for fixture, escaped := range table {
+		t.Run(fixture, func(t *testing.T) {
+			result := internal.ValidateObject(os.DirFS(filepath.Join(badObjPath, fixture)))
+			fatal := result.Fatal()
+			if len(fatal) != 1 {
+				t.Fatalf("expected one error, got %v", fatal)
+			}
+			if !strings.Contains(fatal[0].Error(), escaped) {
+				t.Errorf("expected error to include %s, got: %s", escaped, fatal[0].Error())
+			}
+		})
+	}
+	t.Run("latin1 error type", func(t *testing.T) {
+		result := internal.ValidateObject(os.DirFS(filepath.Join(badObjPath, `E033_latin1_logical_path`)))
+		var strErr *internal.StringInvalidErr
+		if !errors.As(result.Fatal()[0], &strErr) {
+			t.Fatalf("expected StringInvalidErr, got %v", result.Fatal()[0])
+		}
+		if strErr.Value != "caf\xe9.txt" {
+			t.Errorf("unexpected value: %q", strErr.Value)
+		}
+	})
+}
+
+func TestInventoryStringsWarnings(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `minimal_one_version_one_file`))
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	v1 := inv["versions"].(map[string]interface{})["v1"].(map[string]interface{})
+	v1["message"] = "null\x00message"
+	v1["user"].(map[string]interface{})["name"] = "A\nPerson"
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v1`, inv)
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	var got []string
+	for _, w := range result.Warning() {
+		var strErr *internal.StringInvalidErr
+		if errors.As(w, &strErr) {
+			got = append(got, strErr.Error())
+		}
+	}
+	expect := []string{
+		`v1 message includes control characters: "null\x00message"`,
+		`v1 user name includes control characters: "A\nPerson"`,
+	}
+	if strings.Join(got, "|") != strings.Join(expect, "|") {
+		t.Errorf("expected warnings %q, got %q", expect, got)
+	}
+}
+
+func TestDigestMapAddControlChar(t *testing.T) {
+	var dm internal.DigestMap
+	var pathErr *internal.PathInvalidErr
+	if err := dm.Add("abcd", "a\tfile.txt"); !errors.As(err, &pathErr) {
+		t.Errorf("expected PathInvalidErr, got %v", err)
+	}
+	if err := dm.Add("abcd", "caf\xe9.txt"); !errors.As(err, &pathErr) {
+		t.Errorf("expected PathInvalidErr, got %v", err)
+	}
+	if err := dm.Add("abcd", "café.txt"); err != nil {
+		t.Error(err)
+	}
+}
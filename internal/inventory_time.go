@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// TimeRef is the result of resolving a time to a version with
+// Inventory.ResolveTime.
+type TimeRef struct {
+	Version string // version name
+	// Warning is set if version created timestamps are not in the same order
+	// as version numbers. In this case, the version is chosen by timestamp.
+	Warning error
+}
+
+// ResolveTime returns a TimeRef for the latest version created at or before t.
+// If two versions have the same created timestamp, the higher version is
+// used. Timestamps are compared as stored, including offsets. An error
+// wrapping ErrVersionNotFound is returned if no version was created at or
+// before t.
+func (inv *Inventory) ResolveTime(t time.Time) (TimeRef, error) {
+	var ref TimeRef
+	chron, _ := inv.Chronology()
+	var found *VersionTime
+	for i := range chron {
+		vt := &chron[i]
+		if i > 0 && vt.Created.Before(chron[i-1].Created) && ref.Warning == nil {
+			ref.Warning = fmt.Errorf("version %s was created before %s", vt.Version, chron[i-1].Version)
+		}
+		// versions missing from the inventory have no created timestamp
+		if inv.Versions[vt.Version] == nil || vt.Created.After(t) {
+			continue
+		}
+		// chron is in ascending order, so ties go to the higher version
+		if found == nil || !vt.Created.Before(found.Created) {
+			found = vt
+			ref.Version = vt.Version
+		}
+	}
+	if found == nil {
+		return ref, fmt.Errorf("%w: no version created at or before %s",
+			ErrVersionNotFound, t.Format(time.RFC3339))
+	}
+	return ref, nil
+}
+
+// ErrCreatedOrder is the code for warnings about versions created before
+// the previous version. It isn't an OCFL spec code, but it can be used to
+// promote these warnings with Profile.Promote.
+var ErrCreatedOrder = OCFLCodeErr{
+	Description: "Version created timestamps should not decrease.",
+	Code:        "CREATED-ORDER",
+}
+
+// VersionTime is a version name and its created timestamp.
+type VersionTime struct {
+	Version string
+	Created time.Time
+}
+
+// Chronology returns the inventory's versions and their created timestamps
+// in version number order. ok is false if any version was created before
+// the previous version. Equal timestamps are in order.
+func (inv *Inventory) Chronology() (versions []VersionTime, ok bool) {
+	names := inv.VersionDirs()
+	sortVersions(names)
+	ok = true
+	versions = make([]VersionTime, 0, len(names))
+	for i, name := range names {
+		vt := VersionTime{Version: name}
+		if v := inv.Versions[name]; v != nil {
+			vt.Created = v.Created
+		}
+		if i > 0 && vt.Created.Before(versions[i-1].Created) {
+			ok = false
+		}
+		versions = append(versions, vt)
+	}
+	return versions, ok
+}
+
+// createdOrderErrs returns an error for each version created before the
+// previous version.
+func (inv *Inventory) createdOrderErrs() []error {
+	chron, ok := inv.Chronology()
+	if ok {
+		return nil
+	}
+	var errs []error
+	for i := 1; i < len(chron); i++ {
+		prev, v := chron[i-1], chron[i]
+		if v.Created.Before(prev.Created) {
+			errs = append(errs, fmt.Errorf("version %s created (%s) before %s (%s)",
+				v.Version, v.Created.Format(time.RFC3339Nano),
+				prev.Version, prev.Created.Format(time.RFC3339Nano)))
+		}
+	}
+	return errs
+}
+
+// VersionAt returns the name of the latest version created at or before t.
+// See ResolveTime.
+func (inv *Inventory) VersionAt(t time.Time) (string, error) {
+	ref, err := inv.ResolveTime(t)
+	if err != nil {
+		return "", err
+	}
+	return ref.Version, nil
+}
+
+// VersionFSAt returns an fs.FS for the logical state of the latest version
+// created at or before t. See Inventory.ResolveTime.
+func (obj *ObjectReader) VersionFSAt(t time.Time) (fs.FS, error) {
+	vname, err := obj.inventory.VersionAt(t)
+	if err != nil {
+		return nil, err
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		return nil, err
+	}
+	return fs.Sub(logical, vname)
+}
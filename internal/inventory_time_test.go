@@ -0,0 +1,213 @@
+package internal_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestVersionAt(t *testing.T) {
+	inv := readFixtureInventory(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	table := map[string]string{
+		`2018-01-01T01:01:01Z`:      `v1`,
+		`2018-02-01T00:00:00Z`:      `v1`,
+		`2018-02-02T02:02:02Z`:      `v2`,
+		`2018-02-02T00:02:02-02:00`: `v2`, // same instant, different offset
+		`2018-02-02T02:02:01Z`:      `v1`,
+		`2021-06-01T00:00:00Z`:      `v3`,
+	}
+	for in, expected := range table {
+		got, err := inv.VersionAt(mustTime(t, in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Errorf("VersionAt(%s): expected %s, got %s", in, expected, got)
+		}
+	}
+	_, err := inv.VersionAt(mustTime(t, `2017-01-01T00:00:00Z`))
+	if !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestResolveTimeUnordered(t *testing.T) {
+	inv := readFixtureInventory(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	// v2 created after v3
+	inv.Versions[`v2`].Created = mustTime(t, `2018-04-04T04:04:04Z`)
+	ref, err := inv.ResolveTime(mustTime(t, `2018-03-10T00:00:00Z`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Version != `v3` {
+		t.Errorf("expected v3, got %s", ref.Version)
+	}
+	if ref.Warning == nil {
+		t.Error("expected a warning for unordered versions")
+	}
+	ref, err = inv.ResolveTime(mustTime(t, `2018-05-01T00:00:00Z`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Version != `v2` {
+		t.Errorf("expected v2, got %s", ref.Version)
+	}
+	// equal timestamps: higher version
+	inv.Versions[`v2`].Created = inv.Versions[`v3`].Created
+	ref, err = inv.ResolveTime(inv.Versions[`v3`].Created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Version != `v3` || ref.Warning != nil {
+		t.Errorf("expected v3 without warning, got %s, %v", ref.Version, ref.Warning)
+	}
+}
+
+func TestResolveTimeNilVersion(t *testing.T) {
+	inv := readFixtureInventory(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	inv.Versions[`v2`] = nil
+	ref, err := inv.ResolveTime(mustTime(t, `2021-06-01T00:00:00Z`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Version != `v3` {
+		t.Errorf("expected v3, got %s", ref.Version)
+	}
+	ref, err = inv.ResolveTime(mustTime(t, `2018-03-01T00:00:00Z`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Version != `v1` {
+		t.Errorf("expected v1, got %s", ref.Version)
+	}
+}
+
+func TestVersionFSAt(t *testing.T) {
+	obj, err := internal.NewObjectReader(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfs, err := obj.VersionFSAt(mustTime(t, `2018-01-15T00:00:00Z`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat(vfs, `foo/bar.xml`); err != nil {
+		t.Error(err)
+	}
+	if _, err := fs.Stat(vfs, `empty2.txt`); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected empty2.txt to be absent from v1, got %v", err)
+	}
+}
+
+// createdFixture returns the spec-ex-full fixture with version created
+// values replaced.
+func createdFixture(t *testing.T, created map[string]string) fstest.MapFS {
+	t.Helper()
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	versions := inv["versions"].(map[string]interface{})
+	for name, val := range created {
+		versions[name].(map[string]interface{})["created"] = val
+	}
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v3`, inv)
+	return fsys
+}
+
+func TestCreatedOrder(t *testing.T) {
+	table := map[string]struct {
+		created map[string]string
+		ordered bool
+		warning string
+	}{
+		`in order`: {
+			ordered: true,
+		},
+		`equal`: {
+			created: map[string]string{"v2": "2018-01-01T01:01:01Z"},
+			ordered: true,
+		},
+		`out of order`: {
+			created: map[string]string{"v3": "2018-02-02T02:02:01Z"},
+			warning: `version v3 created (2018-02-02T02:02:01Z) before v2 (2018-02-02T02:02:02Z)`,
+		},
+		`out of order with offset`: {
+			created: map[string]string{"v2": "2018-01-01T01:01:01+01:00"},
+			warning: `version v2 created (2018-01-01T01:01:01+01:00) before v1 (2018-01-01T01:01:01Z)`,
+		},
+	}
+	for name, tcase := range table {
+		t.Run(name, func(t *testing.T) {
+			fsys := createdFixture(t, tcase.created)
+			inv, err := internal.ReadInventory(bytes.NewReader(fsys[`inventory.json`].Data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			chron, ordered := inv.Chronology()
+			if ordered != tcase.ordered {
+				t.Errorf("Chronology: expected ordered=%v", tcase.ordered)
+			}
+			if len(chron) != 3 || chron[0].Version != "v1" || chron[2].Version != "v3" {
+				t.Errorf("Chronology: unexpected versions: %v", chron)
+			}
+			result := internal.ValidateObject(fsys)
+			if !result.Valid() {
+				t.Fatal(result.Fatal())
+			}
+			var warnings []string
+			for _, w := range result.Warning() {
+				if errors.Is(w, &internal.ErrCreatedOrder) {
+					warnings = append(warnings, w.Error())
+				}
+			}
+			switch {
+			case tcase.warning == "" && len(warnings) > 0:
+				t.Errorf("unexpected warnings: %v", warnings)
+			case tcase.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tcase.warning)):
+				t.Errorf("expected warning %q, got %v", tcase.warning, warnings)
+			}
+			// promoted by profile
+			profile := &internal.Profile{Promote: []string{internal.ErrCreatedOrder.Code}}
+			result = internal.ValidateObject(fsys, internal.WithProfile(profile))
+			if result.Valid() != tcase.ordered {
+				t.Errorf("with promoted warning, expected valid=%v", tcase.ordered)
+			}
+		})
+	}
+}
+
+func TestCreatedNoTimezone(t *testing.T) {
+	fsys := createdFixture(t, map[string]string{"v2": "2018-02-02T02:02:02"})
+	result := internal.ValidateObject(fsys)
+	if result.Valid() {
+		t.Fatal("expected validation to fail")
+	}
+	err := result.Fatal()[0]
+	if err.Code() != "E049" {
+		t.Errorf("expected E049, got %s", err.Code())
+	}
+	if !strings.Contains(err.Error(), "version v2 created") {
+		t.Errorf("expected error to name the version, got: %s", err.Error())
+	}
+}
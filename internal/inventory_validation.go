@@ -20,6 +20,25 @@ func init() {
 	invJsonSchema = jsonschema.Must(string(schema.InventorySchema))
 }
 
+// InventorySchemaErr is an inventory's violation of the OCFL inventory JSON
+// schema.
+type InventorySchemaErr struct {
+	Pointer string // JSON pointer (RFC 6901) to the invalid value
+	Message string
+}
+
+func (e *InventorySchemaErr) Error() string {
+	return fmt.Sprintf("inventory schema violation at %q: %s", e.Pointer, e.Message)
+}
+
+// jsonPointer converts a property path from jsonschema to a JSON pointer
+func jsonPointer(propPath string) string {
+	if propPath == "/" {
+		return ""
+	}
+	return propPath
+}
+
 func validateInventoryBytes(inv []byte) validationResult {
 	result := validationResult{}
 	errs, err := invJsonSchema.ValidateBytes(context.Background(), inv)
@@ -27,7 +46,16 @@ func validateInventoryBytes(inv []byte) validationResult {
 		result.AddFatal(err, nil)
 		return result
 	}
-	for _, e := range errs {
+	for _, keyErr := range errs {
+		e := &InventorySchemaErr{
+			Pointer: jsonPointer(keyErr.PropertyPath),
+			Message: keyErr.Message,
+		}
+		// fixity algorithms not in the schema are checked if they are
+		// supported and reported as warnings otherwise (see validateFixity)
+		if e.Pointer == `/fixity` && strings.Contains(e.Message, `additional properties`) {
+			continue
+		}
 		// FIXME this string matching business is crude
 		if strings.Contains(e.Message, `"id"`) {
 			result.AddFatal(e, &ErrE036)
@@ -131,9 +159,10 @@ func (inv *Inventory) Validate() error {
 	//	correspond to an entry in the manifest of the inventory.'
 	// E095 - 'Within a version, logical paths must be unique and non-conflicting, so the
 	//	logical path for a file cannot appear as the initial part of another logical path.'
-	for _, v := range inv.Versions {
+	for vname, v := range inv.Versions {
 		err := v.State.Valid()
 		if err != nil {
+			err = fmt.Errorf("%s state: %w", vname, err)
 			var dcErr *DigestConflictErr
 			if errors.As(err, &dcErr) {
 				// FIXME - E050 seems wrong
@@ -153,23 +182,21 @@ func (inv *Inventory) Validate() error {
 		for digest := range v.State {
 			if _, exists := inv.Manifest[digest]; !exists {
 				return &validationErr{
-					err:  fmt.Errorf("digest in % state not in manifest: %s", v, digest),
+					err:  fmt.Errorf("digest in %s state not in manifest: %s", vname, digest),
 					code: &ErrE050,
 				}
 			}
 		}
 	}
 	// check that each manifest entry is used in at least one state
-	for digest := range inv.Manifest {
-		var found bool
-		for _, version := range inv.Versions {
-			for d := range version.State {
-				if digest == d {
-					found = true
-				}
-			}
+	used := map[string]bool{}
+	for _, version := range inv.Versions {
+		for d := range version.State {
+			used[d] = true
 		}
-		if !found {
+	}
+	for digest := range inv.Manifest {
+		if !used[digest] {
 			// This error code is used in the fixture
 			// but doesn't makesense
 			return &validationErr{
@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInventorySchemaErr(t *testing.T) {
+	digest := fmt.Sprintf("%0128x", 0xabc)
+	inv := []byte(`{
+		"id": "ark:123/abc",
+		"type": "https://ocfl.io/1.0/spec/#inventory",
+		"digestAlgorithm": "sha512",
+		"head": "v1",
+		"manifest": {"00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000abc": "v1/content/a.txt"},
+		"versions": {"v1": {"created": "2021-01-01T01:01:01Z", "state": {"00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000abc": ["a.txt"]}}}
+	}`)
+	result := validateInventoryBytes(inv)
+	if result.Valid() {
+		t.Fatal("expected schema errors")
+	}
+	var found bool
+	for _, err := range result.Fatal() {
+		var schemaErr *InventorySchemaErr
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("expected InventorySchemaErr, got %v", err)
+		}
+		if schemaErr.Pointer == `/manifest/`+digest {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected error at /manifest/%s: %v", digest, result.Fatal())
+	}
+}
+
+func TestReadInventorySchemaContinues(t *testing.T) {
+	// missing id (schema) and head doesn't exist (structural)
+	inv := []byte(`{
+		"type": "https://ocfl.io/1.0/spec/#inventory",
+		"digestAlgorithm": "sha512",
+		"head": "v2",
+		"manifest": {"abc": ["v1/content/a.txt"]},
+		"versions": {"v1": {"created": "2021-01-01T01:01:01Z", "state": {"abc": ["a.txt"]}}}
+	}`)
+	root := objectRoot{FS: fstest.MapFS{`inventory.json`: &fstest.MapFile{Data: inv}}}
+	_, err := root.readInventory(`.`, true)
+	var result *validationResult
+	if !errors.As(err, &result) {
+		t.Fatalf("expected validationResult, got %v", err)
+	}
+	var schemaErrs, otherErrs int
+	for _, err := range result.Fatal() {
+		var schemaErr *InventorySchemaErr
+		if errors.As(err, &schemaErr) {
+			schemaErrs++
+			continue
+		}
+		otherErrs++
+	}
+	if schemaErrs == 0 || otherErrs == 0 {
+		t.Errorf("expected schema and structural errors, got %v", result.Fatal())
+	}
+}
+
+func BenchmarkInventorySchema(b *testing.B) {
+	inv := largeInventory(b, 1000)
+	b.Run("schema", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if result := validateInventoryBytes(inv); !result.Valid() {
+				b.Fatal(result.Fatal())
+			}
+		}
+	})
+	b.Run("parse", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := ReadInventory(bytes.NewReader(inv)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// largeInventory returns a valid inventory with one version of n files
+func largeInventory(b *testing.B, n int) []byte {
+	manifest := map[string][]string{}
+	state := map[string][]string{}
+	for i := 0; i < n; i++ {
+		digest := fmt.Sprintf("%0128x", i)
+		manifest[digest] = []string{fmt.Sprintf("v1/content/file-%d.txt", i)}
+		state[digest] = []string{fmt.Sprintf("file-%d.txt", i)}
+	}
+	inv := map[string]interface{}{
+		"id":              "ark:123/abc",
+		"type":            "https://ocfl.io/1.0/spec/#inventory",
+		"digestAlgorithm": "sha512",
+		"head":            "v1",
+		"manifest":        manifest,
+		"versions": map[string]interface{}{
+			"v1": map[string]interface{}{
+				"created": "2021-01-01T01:01:01Z",
+				"state":   state,
+			},
+		},
+	}
+	data, err := json.Marshal(inv)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func TestInventoryStateConflict(t *testing.T) {
+	inv := &Inventory{
+		ID:               "ark:123/abc",
+		Head:             "v2",
+		DigestAlgorithm:  SHA512,
+		ContentDirectory: contentDir,
+		Manifest: DigestMap{
+			"abc": {"v1/content/a"},
+			"def": {"v2/content/a/b"},
+		},
+		Versions: map[string]*Version{
+			// a is a file in v1 and a directory in v2: not a conflict
+			"v1": {State: DigestMap{"abc": {"a"}}},
+			"v2": {State: DigestMap{"def": {"a/b"}}},
+		},
+	}
+	if err := inv.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := inv.Versions["v2"].StateTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := tree.Get("a/b"); err != nil || val != "def" {
+		t.Errorf("unexpected value for a/b: %v, %v", val, err)
+	}
+	// a is a file and a directory in v2
+	inv.Versions["v2"].State["abc"] = []string{"a"}
+	err = inv.Validate()
+	if !errors.Is(err, &ErrE095) {
+		t.Fatalf("expected E095, got %v", err)
+	}
+	for _, s := range []string{"v2", "a/b"} {
+		if !bytes.Contains([]byte(err.Error()), []byte(s)) {
+			t.Errorf("expected error to include %q: %v", s, err)
+		}
+	}
+	var conflict *PathConflictErr
+	if _, err := inv.Versions["v2"].StateTree(); !errors.As(err, &conflict) || conflict.Path != "a" {
+		t.Errorf("expected path conflict for a, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/srerickson/ocfl/internal"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+// skewedObject returns an object with many small files and one large file,
+// which is the last content file in lexical order.
+func skewedObject(t testing.TB, files int, large int) (fstest.MapFS, string) {
+	t.Helper()
+	gen := ocfltest.NewObject(t, ocfltest.WithVersions(1), ocfltest.WithFiles(files), ocfltest.WithSizes(512, 1024))
+	fsys := gen.FS
+	var last string
+	for name := range fsys {
+		if strings.HasPrefix(name, `v1/content/`) && name > last {
+			last = name
+		}
+	}
+	data := make([]byte, large)
+	rand.New(rand.NewSource(1)).Read(data)
+	oldDigest := sha512Hex(fsys[last].Data)
+	newDigest := sha512Hex(data)
+	fsys[last] = &fstest.MapFile{Data: data}
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	manifest := inv["manifest"].(map[string]interface{})
+	manifest[newDigest] = manifest[oldDigest]
+	delete(manifest, oldDigest)
+	state := inv["versions"].(map[string]interface{})["v1"].(map[string]interface{})["state"].(map[string]interface{})
+	state[newDigest] = state[oldDigest]
+	delete(state, oldDigest)
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v1`, inv)
+	return fsys, last
+}
+
+// slowFS records the order content files are opened and, if delay is set,
+// sleeps for delay for every 32 KiB read from them.
+type slowFS struct {
+	fstest.MapFS
+	delay time.Duration
+	mx    sync.Mutex
+	opens []string
+}
+
+func (fsys *slowFS) Open(name string) (fs.File, error) {
+	f, err := fsys.MapFS.Open(name)
+	if err != nil || !strings.Contains(name, `/content/`) {
+		return f, err
+	}
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return f, err
+	}
+	fsys.mx.Lock()
+	fsys.opens = append(fsys.opens, name)
+	fsys.mx.Unlock()
+	return &slowFile{File: f, delay: fsys.delay}, nil
+}
+
+type slowFile struct {
+	fs.File
+	delay time.Duration
+}
+
+func (f *slowFile) Read(p []byte) (int, error) {
+	if len(p) > 32<<10 {
+		p = p[:32<<10]
+	}
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	return f.File.Read(p)
+}
+
+func TestLargestFirst(t *testing.T) {
+	defer func(n int) { internal.NumDigesters = n }(internal.NumDigesters)
+	internal.NumDigesters = 1
+	objFS, large := skewedObject(t, 20, 256<<10)
+	for _, largestFirst := range []bool{false, true} {
+		fsys := &slowFS{MapFS: objFS}
+		var opts []internal.ObjectOption
+		if largestFirst {
+			opts = append(opts, internal.WithLargestFirst())
+		}
+		result := internal.ValidateObject(fsys, opts...)
+		if !result.Valid() {
+			t.Fatal(result.Fatal())
+		}
+		if len(fsys.opens) != 20 {
+			t.Fatalf("expected 20 content files opened, got %d", len(fsys.opens))
+		}
+		if first := fsys.opens[0] == large; first != largestFirst {
+			t.Errorf("largestFirst=%v: first file opened was %s", largestFirst, fsys.opens[0])
+		}
+	}
+
+	// ScanContent
+	fsys := &slowFS{MapFS: objFS}
+	obj, err := internal.NewObjectReader(fsys, internal.WithLargestFirst())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.ScanContent(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fsys.opens[0] != large {
+		t.Errorf("expected %s to be opened first, got %s", large, fsys.opens[0])
+	}
+}
+
+// BenchmarkLargestFirst validates an object with 400 small files and one 8
+// MiB file, read at 32 KiB per millisecond, with 8 digesters. In arrival
+// order, the large file is digested last.
+func BenchmarkLargestFirst(b *testing.B) {
+	defer func(n int) { internal.NumDigesters = n }(internal.NumDigesters)
+	internal.NumDigesters = 8
+	objFS, _ := skewedObject(b, 400, 8<<20)
+	for _, bench := range []struct {
+		name string
+		opts []internal.ObjectOption
+	}{
+		{name: "arrival"},
+		{name: "largest-first", opts: []internal.ObjectOption{internal.WithLargestFirst()}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fsys := &slowFS{MapFS: objFS, delay: time.Millisecond}
+				if result := internal.ValidateObject(fsys, bench.opts...); !result.Valid() {
+					b.Fatal(result.Fatal())
+				}
+			}
+		})
+	}
+}
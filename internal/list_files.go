@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrListTokenInvalid indicates a continuation token passed to ListFiles is
+// malformed or was created for a different object head.
+var ErrListTokenInvalid = errors.New(`invalid or stale list continuation token`)
+
+// ListOption is used to configure ObjectReader.ListFiles
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	prefix   string
+	dirs     bool
+	pageSize int
+	token    string
+}
+
+// WithListPrefix limits ListFiles to logical paths beginning with prefix. In
+// directory mode (see WithListDirs), prefix is a directory path.
+func WithListPrefix(prefix string) ListOption {
+	return func(opts *listOptions) {
+		opts.prefix = prefix
+	}
+}
+
+// WithListDirs configures ListFiles to return the immediate children of the
+// prefix directory. Subdirectories are returned as a single entry with the
+// number of files they contain.
+func WithListDirs() ListOption {
+	return func(opts *listOptions) {
+		opts.dirs = true
+	}
+}
+
+// WithListPage sets the page size and continuation token for ListFiles. The
+// token should be empty for the first page, and FileList.Next for subsequent
+// pages. If size is less than 1, all entries are returned.
+func WithListPage(size int, token string) ListOption {
+	return func(opts *listOptions) {
+		opts.pageSize = size
+		opts.token = token
+	}
+}
+
+// FileList is a page of entries returned by ListFiles.
+type FileList struct {
+	Entries []FileEntry
+	// Next is the continuation token for the next page. It is empty if there
+	// are no more entries.
+	Next string
+}
+
+// FileEntry is a logical file or directory returned by ListFiles
+type FileEntry struct {
+	Path  string // logical path
+	IsDir bool
+	// Count is the number of files in a directory entry's subtree
+	Count int
+	// Digest is the file's content digest
+	Digest string
+	// Introduced is the version that introduced the file's content
+	Introduced string
+}
+
+// ListFiles returns a sorted page of entries in the logical state of version
+// vname. Continuation tokens are valid as long as the object's head doesn't
+// change; otherwise, an error wrapping ErrListTokenInvalid is returned.
+func (obj *ObjectReader) ListFiles(vname string, opts ...ListOption) (FileList, error) {
+	var list FileList
+	var conf listOptions
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	version, ok := obj.inventory.Versions[vname]
+	if !ok {
+		return list, fmt.Errorf("%w: %s", ErrVersionNotFound, vname)
+	}
+	after, err := obj.decodeListToken(vname, conf.token)
+	if err != nil {
+		return list, err
+	}
+	var entries []FileEntry
+	if conf.dirs {
+		tree, err := version.StateTree()
+		if err != nil {
+			return list, err
+		}
+		entries = obj.listDirEntries(tree, conf.prefix)
+	} else {
+		paths, err := version.State.Paths()
+		if err != nil {
+			return list, err
+		}
+		entries = obj.listEntries(paths, conf.prefix)
+	}
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Path > after
+	})
+	entries = entries[start:]
+	if conf.pageSize > 0 && len(entries) > conf.pageSize {
+		entries = entries[:conf.pageSize]
+		list.Next = obj.encodeListToken(vname, entries[len(entries)-1].Path)
+	}
+	list.Entries = entries
+	return list, nil
+}
+
+// listEntries returns sorted entries for logical paths (in the paths ->
+// digest map) with the given prefix
+func (obj *ObjectReader) listEntries(paths map[string]string, prefix string) []FileEntry {
+	var entries []FileEntry
+	for p, digest := range paths {
+		if strings.HasPrefix(p, prefix) {
+			entries = append(entries, obj.fileEntry(p, digest))
+		}
+	}
+	sortEntries(entries)
+	return entries
+}
+
+// listDirEntries returns sorted entries for the children of directory dir
+// in the logical state tree
+func (obj *ObjectReader) listDirEntries(tree *PathTree, dir string) []FileEntry {
+	dir = strings.Trim(dir, "/")
+	if dir == "" {
+		dir = "."
+	}
+	val, err := tree.Get(dir)
+	if err != nil {
+		return nil
+	}
+	node, ok := val.(*PathTree)
+	if !ok {
+		return nil
+	}
+	var entries []FileEntry
+	for name, digest := range node.Files {
+		entries = append(entries, obj.fileEntry(path.Join(dir, name), digest.(string)))
+	}
+	for name, sub := range node.Dirs {
+		entries = append(entries, FileEntry{Path: path.Join(dir, name), IsDir: true, Count: sub.Len()})
+	}
+	sortEntries(entries)
+	return entries
+}
+
+func sortEntries(entries []FileEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+}
+
+func (obj *ObjectReader) fileEntry(p string, digest string) FileEntry {
+	entry := FileEntry{Path: p, Digest: digest}
+	if contentPaths := obj.inventory.Manifest[digest]; len(contentPaths) > 0 {
+		entry.Introduced, _ = obj.inventory.VersionOfContentPath(contentPaths[0])
+	}
+	return entry
+}
+
+// list tokens encode the head, the listed version, and the last path
+func (obj *ObjectReader) encodeListToken(vname string, last string) string {
+	raw := strings.Join([]string{obj.inventory.Head, vname, last}, "\n")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func (obj *ObjectReader) decodeListToken(vname string, token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrListTokenInvalid, err.Error())
+	}
+	parts := strings.SplitN(string(raw), "\n", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return "", ErrListTokenInvalid
+	}
+	if parts[0] != obj.inventory.Head || parts[1] != vname {
+		return "", fmt.Errorf("%w: token for %s (head %s)", ErrListTokenInvalid, parts[1], parts[0])
+	}
+	return parts[2], nil
+}
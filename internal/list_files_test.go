@@ -0,0 +1,118 @@
+package internal_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestListFiles(t *testing.T) {
+	obj, err := internal.NewObjectReader(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := obj.ListFiles(`v3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for _, e := range list.Entries {
+		paths = append(paths, e.Path)
+	}
+	expected := []string{`empty2.txt`, `foo/bar.xml`, `image.tiff`}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, paths)
+		}
+	}
+	if list.Entries[1].Introduced != `v2` || list.Entries[2].Introduced != `v1` {
+		t.Errorf("unexpected introduced versions: %v", list.Entries)
+	}
+	if list.Next != "" {
+		t.Error("expected no continuation token")
+	}
+
+	// prefix
+	list, err = obj.ListFiles(`v3`, internal.WithListPrefix(`foo/`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Entries) != 1 || list.Entries[0].Path != `foo/bar.xml` {
+		t.Errorf("unexpected entries with prefix: %v", list.Entries)
+	}
+
+	// directory mode
+	list, err = obj.ListFiles(`v3`, internal.WithListDirs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Entries) != 3 {
+		t.Fatalf("unexpected entries in dir mode: %v", list.Entries)
+	}
+	if dir := list.Entries[1]; dir.Path != `foo` || !dir.IsDir || dir.Count != 1 {
+		t.Errorf("unexpected directory entry: %v", dir)
+	}
+
+	// pages
+	var all []internal.FileEntry
+	var token string
+	for {
+		list, err = obj.ListFiles(`v3`, internal.WithListPage(2, token))
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, list.Entries...)
+		if list.Next == "" {
+			break
+		}
+		token = list.Next
+	}
+	if len(all) != 3 || all[2].Path != `image.tiff` {
+		t.Errorf("unexpected paged entries: %v", all)
+	}
+
+	// token for a different version
+	list, err = obj.ListFiles(`v3`, internal.WithListPage(1, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = obj.ListFiles(`v2`, internal.WithListPage(1, list.Next))
+	if !errors.Is(err, internal.ErrListTokenInvalid) {
+		t.Errorf("expected ErrListTokenInvalid, got %v", err)
+	}
+	_, err = obj.ListFiles(`v3`, internal.WithListPage(1, "!!"))
+	if !errors.Is(err, internal.ErrListTokenInvalid) {
+		t.Errorf("expected ErrListTokenInvalid, got %v", err)
+	}
+}
+
+func TestListFilesStaleToken(t *testing.T) {
+	dir := filepath.Join(goodObjPath, `spec-ex-full`)
+	old, full := rollbackFixture(t, dir)
+	obj, err := internal.NewObjectReader(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := obj.ListFiles(`v1`, internal.WithListPage(1, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Next == "" {
+		t.Fatal("expected a continuation token")
+	}
+	// head moved from v2 to v3
+	obj, err = internal.NewObjectReader(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = obj.ListFiles(`v1`, internal.WithListPage(1, list.Next))
+	if !errors.Is(err, internal.ErrListTokenInvalid) {
+		t.Errorf("expected ErrListTokenInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ListingOption configures ValidateAgainstListing
+type ListingOption func(*listingOptions)
+
+type listingOptions struct {
+	sizes map[string]int64
+	stats *VersionStatsFile
+}
+
+// WithExpectedSizes sets expected sizes for content paths. Content paths in
+// the listing with different sizes are reported as E092 errors.
+func WithExpectedSizes(sizes map[string]int64) ListingOption {
+	return func(opts *listingOptions) {
+		opts.sizes = sizes
+	}
+}
+
+// WithListingStats sets the contents of the object's version stats
+// extension file. Version file counts and sizes calculated from the listing
+// are checked against it, and differences are reported as warnings.
+func WithListingStats(stats VersionStatsFile) ListingOption {
+	return func(opts *listingOptions) {
+		opts.stats = &stats
+	}
+}
+
+// ValidateAgainstListing validates the object with inventory inv using a
+// listing of the object's files, without reading them. Keys in listing are
+// slash-separated paths relative to the object root and values are file
+// sizes. The inventory is validated, the declaration, inventory, and sidecar
+// files are checked for in the listing, manifest and fixity content paths
+// must be listed, and other listed files in version directories are
+// reported. Content fixity and the inventory sidecar's digest aren't
+// checked. An error is returned if listing includes invalid paths.
+func ValidateAgainstListing(inv *Inventory, listing map[string]int64, opts ...ListingOption) (ValidationResult, error) {
+	result := &validationResult{}
+	result.setConformance()
+	defer DefaultProfile.apply(result)
+	var conf listingOptions
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if inv == nil {
+		return result, errors.New("inventory is nil")
+	}
+	for name := range listing {
+		if !validPath(name) {
+			return result, &PathInvalidErr{Path: name}
+		}
+	}
+	if err := inv.Validate(); err != nil {
+		result.AddFatal(err, nil)
+		return result, nil
+	}
+	specVersion, err := inv.SpecVersion()
+	if err != nil {
+		result.AddFatal(err, &ErrE038)
+		return result, nil
+	}
+	// object root
+	decl := Declaration{Kind: DeclObject, Version: specVersion}
+	rootFiles := map[string]*OCFLCodeErr{
+		decl.Name():       &ErrE003,
+		inventoryFile:     &ErrE034,
+		inv.SidecarFile(): &ErrE058,
+	}
+	for name, code := range rootFiles {
+		if _, ok := listing[name]; !ok {
+			result.AddFatal(fmt.Errorf("missing %s", name), code)
+		}
+	}
+	// files in version directories by version
+	versionFiles := map[string][]string{}
+	for name := range listing {
+		first := strings.SplitN(name, "/", 2)[0]
+		switch {
+		case first == name && rootFiles[name] != nil:
+		case first == extensionsDir && first != name:
+		case inv.Versions[first] != nil && first != name:
+			versionFiles[first] = append(versionFiles[first], name)
+		default:
+			result.AddFatal(fmt.Errorf("unexpected file in object root: %s", name), &ErrE001)
+		}
+	}
+	manifest, err := inv.Manifest.Normalize()
+	if err != nil {
+		return result, err
+	}
+	contentPaths, err := manifest.Paths()
+	if err != nil {
+		return result, err
+	}
+	vnames := inv.VersionDirs()
+	sortVersions(vnames)
+	for _, v := range vnames {
+		files := versionFiles[v]
+		if len(files) == 0 {
+			result.AddFatal(fmt.Errorf("missing version directory: %s", v), &ErrE046)
+			continue
+		}
+		sort.Strings(files)
+		contentDir := inv.contentRef(v, "").Dir() + "/"
+		var hasInventory bool
+		for _, name := range files {
+			switch name {
+			case path.Join(v, inventoryFile):
+				hasInventory = true
+				continue
+			case path.Join(v, inv.SidecarFile()):
+				continue
+			}
+			if !strings.HasPrefix(name, contentDir) {
+				result.AddFatal(fmt.Errorf("unexpected file in version directory: %s", name), &ErrE015)
+				continue
+			}
+			if _, ok := contentPaths[name]; !ok {
+				result.AddFatal(fmt.Errorf("content file not in manifest: %s", name), &ErrE023)
+			}
+		}
+		if !hasInventory {
+			result.AddWarn(fmt.Errorf(`version directory has no inventory: %s`, v), &ErrW010)
+		} else if _, ok := listing[path.Join(v, inv.SidecarFile())]; !ok {
+			result.AddFatal(fmt.Errorf("missing %s", path.Join(v, inv.SidecarFile())), &ErrE058)
+		}
+	}
+	// manifest and fixity paths
+	for _, name := range sortedKeys(contentPaths) {
+		if _, ok := listing[name]; !ok {
+			result.AddFatal(fmt.Errorf("manifest content path not listed: %s", name), &ErrE023)
+		}
+	}
+	algs := make([]string, 0, len(inv.Fixity))
+	for alg := range inv.Fixity {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+	for _, alg := range algs {
+		for _, paths := range inv.Fixity[alg] {
+			for _, name := range paths {
+				// unlisted manifest paths are already reported
+				_, inManifest := contentPaths[name]
+				if _, ok := listing[name]; !ok && !inManifest {
+					err := fmt.Errorf("%s fixity content path not listed: %s", alg, name)
+					result.AddFatal(err, &ErrE093)
+				}
+			}
+		}
+	}
+	// sizes
+	for _, name := range sortedKeys(contentPaths) {
+		size, listed := listing[name]
+		expected, ok := conf.sizes[name]
+		if listed && ok && size != expected {
+			err := fmt.Errorf("content file size is %d, expected %d: %s", size, expected, name)
+			result.AddFatal(err, &ErrE092)
+		}
+	}
+	if conf.stats != nil {
+		for _, err := range listingStatsErrs(inv, manifest, listing, conf.stats) {
+			result.AddWarn(err, nil)
+		}
+	}
+	return result, nil
+}
+
+// listingStatsErrs compares version stats calculated from listed content
+// sizes with stats. Versions with unlisted content are skipped.
+func listingStatsErrs(inv *Inventory, manifest DigestMap, listing map[string]int64, stats *VersionStatsFile) []error {
+	var errs []error
+	if stats.Head != inv.Head {
+		return []error{fmt.Errorf("%w: written for %s, object head is %s", ErrVersionStatsStale, stats.Head, inv.Head)}
+	}
+	vnames := inv.VersionDirs()
+	sortVersions(vnames)
+	for _, v := range vnames {
+		expected, ok := stats.Versions[v]
+		if !ok {
+			errs = append(errs, fmt.Errorf("version stats missing %s", v))
+			continue
+		}
+		var got VersionStats
+		complete := true
+		for digest, paths := range inv.Versions[v].State {
+			size, ok := contentSize(manifest[strings.ToLower(digest)], listing)
+			if !ok {
+				complete = false
+				break
+			}
+			got.Files += len(paths)
+			got.Bytes += size * int64(len(paths))
+		}
+		if complete && got != expected {
+			errs = append(errs, fmt.Errorf("version stats for %s (%d files, %d bytes) don't match listing (%d files, %d bytes)",
+				v, expected.Files, expected.Bytes, got.Files, got.Bytes))
+		}
+	}
+	return errs
+}
+
+// contentSize returns the listed size of the first listed content path.
+func contentSize(paths []string, listing map[string]int64) (int64, bool) {
+	for _, p := range paths {
+		if size, ok := listing[p]; ok {
+			return size, true
+		}
+	}
+	return 0, false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Listing returns a listing of regular files in fsys, for use with
+// ValidateAgainstListing.
+func Listing(fsys fs.FS) (map[string]int64, error) {
+	listing := map[string]int64{}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		listing[name] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
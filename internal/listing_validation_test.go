@@ -0,0 +1,157 @@
+package internal_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestValidateAgainstListing(t *testing.T) {
+	objPath := filepath.Join(goodObjPath, `spec-ex-full`)
+	inv := readFixtureInventory(t, objPath)
+	table := map[string]struct {
+		edit  func(listing map[string]int64)
+		codes []string
+	}{
+		`unchanged`: {
+			edit: func(map[string]int64) {},
+		},
+		`content removed`: {
+			edit: func(l map[string]int64) {
+				delete(l, `v1/content/foo/bar.xml`)
+			},
+			codes: []string{`E023`},
+		},
+		`content added`: {
+			edit: func(l map[string]int64) {
+				l[`v2/content/extra.txt`] = 10
+			},
+			codes: []string{`E023`},
+		},
+		`file in version dir`: {
+			edit: func(l map[string]int64) {
+				l[`v2/extra.txt`] = 10
+			},
+			codes: []string{`E015`},
+		},
+		`file in root`: {
+			edit: func(l map[string]int64) {
+				l[`extra.txt`] = 10
+			},
+			codes: []string{`E001`},
+		},
+		`extension file`: {
+			edit: func(l map[string]int64) {
+				l[`extensions/example/config.json`] = 10
+			},
+		},
+		`no declaration`: {
+			edit: func(l map[string]int64) {
+				delete(l, `0=ocfl_object_1.0`)
+			},
+			codes: []string{`E003`},
+		},
+		`no sidecar`: {
+			edit: func(l map[string]int64) {
+				delete(l, `inventory.json.sha512`)
+			},
+			codes: []string{`E058`},
+		},
+		`no version sidecar`: {
+			edit: func(l map[string]int64) {
+				delete(l, `v2/inventory.json.sha512`)
+			},
+			codes: []string{`E058`},
+		},
+		`no version dir`: {
+			edit: func(l map[string]int64) {
+				for name := range l {
+					if strings.HasPrefix(name, `v3/`) {
+						delete(l, name)
+					}
+				}
+			},
+			codes: []string{`E046`},
+		},
+	}
+	for name, tcase := range table {
+		t.Run(name, func(t *testing.T) {
+			listing, err := internal.Listing(os.DirFS(objPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tcase.edit(listing)
+			result, err := internal.ValidateAgainstListing(inv, listing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var codes []string
+			for _, err := range result.Fatal() {
+				codes = append(codes, err.Code())
+			}
+			if strings.Join(codes, ",") != strings.Join(tcase.codes, ",") {
+				t.Errorf("expected errors %v, got %v", tcase.codes, result.Fatal())
+			}
+		})
+	}
+}
+
+func TestValidateAgainstListingSizes(t *testing.T) {
+	objPath := filepath.Join(goodObjPath, `spec-ex-full`)
+	inv := readFixtureInventory(t, objPath)
+	listing, err := internal.Listing(os.DirFS(objPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := map[string]int64{`v1/content/foo/bar.xml`: listing[`v1/content/foo/bar.xml`]}
+	result, err := internal.ValidateAgainstListing(inv, listing, internal.WithExpectedSizes(sizes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	sizes[`v1/content/foo/bar.xml`]++
+	result, err = internal.ValidateAgainstListing(inv, listing, internal.WithExpectedSizes(sizes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Fatal()) != 1 || result.Fatal()[0].Code() != `E092` {
+		t.Errorf("expected E092, got %v", result.Fatal())
+	}
+	// version stats
+	obj, err := internal.NewObjectReader(os.DirFS(objPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := obj.RecomputeVersionStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = internal.ValidateAgainstListing(inv, listing, internal.WithListingStats(stats))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Warning()) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warning())
+	}
+	listing[`v1/content/foo/bar.xml`]++
+	result, err = internal.ValidateAgainstListing(inv, listing, internal.WithListingStats(stats))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Warning()) == 0 {
+		t.Error("expected warnings for stats that don't match listing")
+	}
+}
+
+func TestValidateAgainstListingInvalidPath(t *testing.T) {
+	inv := readFixtureInventory(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	if _, err := internal.ValidateAgainstListing(inv, map[string]int64{"/abs": 1}); err == nil {
+		t.Error("expected error for invalid path")
+	}
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"sort"
+)
+
+// Kinds of MergeConflict
+const (
+	// ConflictAddAdd: both sides added the path with different digests
+	ConflictAddAdd = `add/add`
+	// ConflictModifyModify: both sides changed the path's digest
+	// differently
+	ConflictModifyModify = `modify/modify`
+	// ConflictDeleteModify: one side deleted the path and the other changed
+	// its digest
+	ConflictDeleteModify = `delete/modify`
+	// ConflictRename: both sides renamed the same path differently. The
+	// conflict is resolved by keeping ours.
+	ConflictRename = `rename/rename`
+)
+
+// MergeConflict is a logical path that was changed differently by ours and
+// theirs in MergeStates. Digests are empty if the path is absent.
+type MergeConflict struct {
+	Path   string
+	Kind   string
+	Base   string
+	Ours   string
+	Theirs string
+	// Resolved is true if the conflict was resolved automatically
+	Resolved bool
+}
+
+// MergeStates does a three-way merge of version states: ours and theirs
+// are changes to base. Changes made by only one side, or identically by
+// both, are merged. Paths changed differently by both sides are returned
+// as conflicts, and the merged state has ours for those paths. If both sides
+// renamed the same path to different names, ours is kept and a resolved
+// conflict is returned for theirs. Digests are compared case-insensitively.
+// An error is returned if any of the states are invalid, or if the merged
+// state uses a path as both a file and a directory.
+func MergeStates(base, ours, theirs DigestMap) (DigestMap, []MergeConflict, error) {
+	var b, o, t map[string]string
+	for _, s := range []struct {
+		dm    DigestMap
+		paths *map[string]string
+	}{{base, &b}, {ours, &o}, {theirs, &t}} {
+		norm, err := s.dm.Normalize()
+		if err != nil {
+			return nil, nil, err
+		}
+		if *s.paths, err = norm.Paths(); err != nil {
+			return nil, nil, err
+		}
+	}
+	all := map[string]bool{}
+	for _, paths := range []map[string]string{b, o, t} {
+		for p := range paths {
+			all[p] = true
+		}
+	}
+	merged := map[string]string{}
+	var conflicts []MergeConflict
+	for p := range all {
+		bd, od, td := b[p], o[p], t[p]
+		switch {
+		case od == td, td == bd:
+			if od != "" {
+				merged[p] = od
+			}
+			continue
+		case od == bd:
+			if td != "" {
+				merged[p] = td
+			}
+			continue
+		}
+		// both sides changed p differently
+		c := MergeConflict{Path: p, Base: bd, Ours: od, Theirs: td}
+		switch {
+		case bd == "":
+			c.Kind = ConflictAddAdd
+		case od == "" || td == "":
+			c.Kind = ConflictDeleteModify
+		default:
+			c.Kind = ConflictModifyModify
+		}
+		if od != "" {
+			merged[p] = od
+		}
+		conflicts = append(conflicts, c)
+	}
+	conflicts = append(conflicts, resolveRenames(b, o, t, merged)...)
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	result := DigestMap{}
+	for p, d := range merged {
+		result[d] = append(result[d], p)
+	}
+	for _, paths := range result {
+		sort.Strings(paths)
+	}
+	if err := result.Valid(); err != nil {
+		return nil, conflicts, err
+	}
+	return result, conflicts, nil
+}
+
+// resolveRenames finds base paths that both sides deleted while adding the
+// same digest at different new paths. Theirs new paths are removed from
+// merged and returned as resolved conflicts.
+func resolveRenames(b, o, t, merged map[string]string) []MergeConflict {
+	// digests of paths deleted by both sides
+	deleted := map[string]bool{}
+	for p, d := range b {
+		if o[p] == "" && t[p] == "" {
+			deleted[d] = true
+		}
+	}
+	// new paths with deleted digests, added by only one side
+	added := func(side, other map[string]string) map[string][]string {
+		adds := map[string][]string{}
+		for p, d := range side {
+			if b[p] == "" && other[p] == "" && deleted[d] {
+				adds[d] = append(adds[d], p)
+			}
+		}
+		return adds
+	}
+	oursAdds, theirsAdds := added(o, t), added(t, o)
+	var conflicts []MergeConflict
+	for d, paths := range theirsAdds {
+		if len(oursAdds[d]) == 0 {
+			continue
+		}
+		for _, p := range paths {
+			delete(merged, p)
+			conflicts = append(conflicts, MergeConflict{
+				Path:     p,
+				Kind:     ConflictRename,
+				Theirs:   d,
+				Resolved: true,
+			})
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,123 @@
+package internal_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestMergeStates(t *testing.T) {
+	base := internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}}
+	table := map[string]struct {
+		ours, theirs internal.DigestMap
+		merged       internal.DigestMap
+		conflicts    []internal.MergeConflict
+	}{
+		`no changes`: {
+			ours:   base,
+			theirs: base,
+			merged: base,
+		},
+		`ours only`: {
+			ours:   internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "dd": {"dir/c.txt", "d.txt"}},
+			theirs: base,
+			merged: internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "dd": {"d.txt", "dir/c.txt"}},
+		},
+		`theirs only`: {
+			ours:   base,
+			theirs: internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}},
+			merged: internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}},
+		},
+		`independent changes`: {
+			ours:   internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "dd": {"d.txt"}},
+			theirs: internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}, "ee": {"e.txt"}},
+			merged: internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}, "dd": {"d.txt"}, "ee": {"e.txt"}},
+		},
+		`same add, same digest`: {
+			ours:   internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "dd": {"d.txt"}},
+			theirs: internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "DD": {"d.txt"}},
+			merged: internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "dd": {"d.txt"}},
+		},
+		`same delete`: {
+			ours:   internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}},
+			theirs: internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}},
+			merged: internal.DigestMap{"aa": {"a.txt"}, "cc": {"dir/c.txt"}},
+		},
+		`add/add`: {
+			ours:      internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "dd": {"d.txt"}},
+			theirs:    internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "ee": {"d.txt"}},
+			merged:    internal.DigestMap{"aa": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}, "dd": {"d.txt"}},
+			conflicts: []internal.MergeConflict{{Path: "d.txt", Kind: internal.ConflictAddAdd, Ours: "dd", Theirs: "ee"}},
+		},
+		`modify/modify`: {
+			ours:      internal.DigestMap{"dd": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			theirs:    internal.DigestMap{"ee": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged:    internal.DigestMap{"dd": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			conflicts: []internal.MergeConflict{{Path: "a.txt", Kind: internal.ConflictModifyModify, Base: "aa", Ours: "dd", Theirs: "ee"}},
+		},
+		`delete/modify`: {
+			ours:      internal.DigestMap{"bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			theirs:    internal.DigestMap{"ee": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged:    internal.DigestMap{"bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			conflicts: []internal.MergeConflict{{Path: "a.txt", Kind: internal.ConflictDeleteModify, Base: "aa", Theirs: "ee"}},
+		},
+		`modify/delete`: {
+			ours:      internal.DigestMap{"ee": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			theirs:    internal.DigestMap{"bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged:    internal.DigestMap{"ee": {"a.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			conflicts: []internal.MergeConflict{{Path: "a.txt", Kind: internal.ConflictDeleteModify, Base: "aa", Ours: "ee"}},
+		},
+		`rename by one side`: {
+			ours:   base,
+			theirs: internal.DigestMap{"aa": {"a2.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged: internal.DigestMap{"aa": {"a2.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+		},
+		`same rename`: {
+			ours:   internal.DigestMap{"aa": {"a2.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			theirs: internal.DigestMap{"aa": {"a2.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged: internal.DigestMap{"aa": {"a2.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+		},
+		`different renames`: {
+			ours:      internal.DigestMap{"aa": {"ours.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			theirs:    internal.DigestMap{"aa": {"theirs.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged:    internal.DigestMap{"aa": {"ours.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			conflicts: []internal.MergeConflict{{Path: "theirs.txt", Kind: internal.ConflictRename, Theirs: "aa", Resolved: true}},
+		},
+		`copy and rename`: {
+			// ours copies a.txt: not a rename
+			ours:   internal.DigestMap{"aa": {"a.txt", "copy.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			theirs: internal.DigestMap{"aa": {"theirs.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+			merged: internal.DigestMap{"aa": {"copy.txt", "theirs.txt"}, "bb": {"b.txt"}, "cc": {"dir/c.txt"}},
+		},
+	}
+	for name, tcase := range table {
+		t.Run(name, func(t *testing.T) {
+			merged, conflicts, err := internal.MergeStates(base, tcase.ours, tcase.theirs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(merged, tcase.merged) {
+				t.Errorf("expected %v, got %v", tcase.merged, merged)
+			}
+			if !reflect.DeepEqual(conflicts, tcase.conflicts) {
+				t.Errorf("expected conflicts %v, got %v", tcase.conflicts, conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeStatesInvalid(t *testing.T) {
+	base := internal.DigestMap{"aa": {"a"}}
+	// ours adds file d and theirs adds d/e: both are valid, but not merged
+	ours := internal.DigestMap{"aa": {"a"}, "bb": {"d"}}
+	theirs := internal.DigestMap{"aa": {"a"}, "cc": {"d/e"}}
+	var conflict *internal.PathConflictErr
+	if _, _, err := internal.MergeStates(base, ours, theirs); !errors.As(err, &conflict) {
+		t.Errorf("expected PathConflictErr, got %v", err)
+	}
+	if _, _, err := internal.MergeStates(base, internal.DigestMap{"aa": {"../a"}}, theirs); err == nil {
+		t.Error("expected error for invalid state")
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"io/fs"
+	"sync"
+
+	"github.com/srerickson/checksum"
+)
+
+// Metric names reported by ObjectReader.
+const (
+	// MetricFilesDigested counts files digested (label: alg)
+	MetricFilesDigested = `ocfl_files_digested_total`
+	// MetricBytesDigested counts bytes digested (label: alg)
+	MetricBytesDigested = `ocfl_bytes_digested_total`
+	// MetricValidationErrors counts validation errors (label: code)
+	MetricValidationErrors = `ocfl_validation_errors_total`
+	// MetricObjectsValidated counts validated objects (label: valid)
+	MetricObjectsValidated = `ocfl_objects_validated_total`
+	// MetricValidationSeconds observes the duration of object validation
+	MetricValidationSeconds = `ocfl_validation_seconds`
+)
+
+// Metrics is an interface for instrumenting long-running operations, like
+// validation. Counters and observations are reported per file, not per
+// buffer. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Add adds val to the named counter
+	Add(name string, labels map[string]string, val float64)
+	// Observe adds an observation to the named histogram
+	Observe(name string, labels map[string]string, val float64)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) Add(string, map[string]string, float64)     {}
+func (noopMetrics) Observe(string, map[string]string, float64) {}
+
+// WithMetrics sets the Metrics used to instrument the ObjectReader.
+func WithMetrics(m Metrics) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.metrics = m
+	}
+}
+
+func (opts *objectOptions) getMetrics() Metrics {
+	if opts.metrics == nil {
+		return noopMetrics{}
+	}
+	return opts.metrics
+}
+
+// recordDigest reports a digested file to the object's metrics
+func (obj *ObjectReader) recordDigest(alg string, size int64) {
+	m := obj.opts.getMetrics()
+	labels := map[string]string{"alg": alg}
+	m.Add(MetricFilesDigested, labels, 1)
+	m.Add(MetricBytesDigested, labels, float64(size))
+}
+
+// sizeIndex records file sizes found during a checksum walk so they can be
+// reported with the digest results. It is safe for concurrent use.
+type sizeIndex struct {
+	mx    sync.Mutex
+	sizes map[string]int64
+}
+
+// walkDirFunc is a fs.WalkDirFunc for checksum.WithWalkDirFunc
+func (idx *sizeIndex) walkDirFunc(p string, d fs.DirEntry, err error) error {
+	if err := checksum.DefaultWalkDirFunc(p, d, err); err != nil {
+		return err
+	}
+	if info, err := d.Info(); err == nil {
+		idx.mx.Lock()
+		if idx.sizes == nil {
+			idx.sizes = make(map[string]int64)
+		}
+		idx.sizes[p] = info.Size()
+		idx.mx.Unlock()
+	}
+	return nil
+}
+
+// pop returns and removes the size for p
+func (idx *sizeIndex) pop(p string) int64 {
+	idx.mx.Lock()
+	defer idx.mx.Unlock()
+	size := idx.sizes[p]
+	delete(idx.sizes, p)
+	return size
+}
+
+// recordDigests reports a file digested with each algorithm in sums
+func (obj *ObjectReader) recordDigests(sums map[string]string, size int64) {
+	for alg := range sums {
+		obj.recordDigest(alg, size)
+	}
+}
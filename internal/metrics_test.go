@@ -0,0 +1,96 @@
+package internal_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// testMetrics is an example Metrics adapter
+type testMetrics struct {
+	mx       sync.Mutex
+	counters map[string]float64
+	observed map[string][]float64
+}
+
+func metricKey(name string, labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (m *testMetrics) Add(name string, labels map[string]string, val float64) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if m.counters == nil {
+		m.counters = make(map[string]float64)
+	}
+	m.counters[metricKey(name, labels)] += val
+}
+
+func (m *testMetrics) Observe(name string, labels map[string]string, val float64) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if m.observed == nil {
+		m.observed = make(map[string][]float64)
+	}
+	key := metricKey(name, labels)
+	m.observed[key] = append(m.observed[key], val)
+}
+
+func TestValidationMetrics(t *testing.T) {
+	dir := filepath.Join(goodObjPath, `spec-ex-full`)
+	metrics := &testMetrics{}
+	result := internal.ValidateObject(os.DirFS(dir), internal.WithMetrics(metrics))
+	if !result.Valid() {
+		t.Fatal(result)
+	}
+	inv := readFixtureInventory(t, dir)
+	var files, size float64
+	for _, paths := range inv.Manifest {
+		for _, p := range paths {
+			info, err := fs.Stat(os.DirFS(dir), p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files++
+			size += float64(info.Size())
+		}
+	}
+	alg := map[string]string{"alg": internal.SHA512}
+	if got := metrics.counters[metricKey(internal.MetricFilesDigested, alg)]; got != files {
+		t.Errorf("expected %v files digested, got %v", files, got)
+	}
+	if got := metrics.counters[metricKey(internal.MetricBytesDigested, alg)]; got != size {
+		t.Errorf("expected %v bytes digested, got %v", size, got)
+	}
+	alg = map[string]string{"alg": internal.MD5}
+	if got := metrics.counters[metricKey(internal.MetricFilesDigested, alg)]; got != 4 {
+		t.Errorf("expected 4 md5 fixity files digested, got %v", got)
+	}
+	valid := map[string]string{"valid": "true"}
+	if got := metrics.counters[metricKey(internal.MetricObjectsValidated, valid)]; got != 1 {
+		t.Errorf("expected 1 valid object, got %v", got)
+	}
+	if got := len(metrics.observed[metricKey(internal.MetricValidationSeconds, nil)]); got != 1 {
+		t.Errorf("expected 1 validation duration, got %v", got)
+	}
+
+	// errors by code
+	metrics = &testMetrics{}
+	dir = filepath.Join(badObjPath, `E092_content_file_digest_mismatch`)
+	internal.ValidateObject(os.DirFS(dir), internal.WithMetrics(metrics))
+	code := map[string]string{"code": "E092"}
+	if got := metrics.counters[metricKey(internal.MetricValidationErrors, code)]; got != 1 {
+		t.Errorf("expected 1 E092 error, got %v", got)
+	}
+}
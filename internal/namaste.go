@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Declaration kinds
+const (
+	DeclObject = `ocfl_object` // object declaration
+	DeclRoot   = `ocfl`        // storage root declaration
+)
+
+var (
+	// ErrDeclarationNotFound indicates a directory has no NAMASTE
+	// declaration.
+	ErrDeclarationNotFound = errors.New(`declaration not found`)
+	// ErrDeclarationMultiple indicates a directory has more than one
+	// NAMASTE declaration.
+	ErrDeclarationMultiple = errors.New(`multiple declarations`)
+	// ErrDeclarationContents indicates a declaration file's contents don't
+	// match its name.
+	ErrDeclarationContents = errors.New(`declaration has invalid text contents`)
+)
+
+var namasteRegexp = regexp.MustCompile(`^0=(` + DeclRoot + `|` + DeclObject + `)_(\d+\.\d+)$`)
+
+// Declaration is a NAMASTE declaration of an OCFL object or storage root,
+// e.g. `0=ocfl_object_1.0`.
+type Declaration struct {
+	Kind    string // DeclObject or DeclRoot
+	Version string // OCFL spec version, e.g. 1.0
+}
+
+// Name returns the declaration's file name.
+func (d Declaration) Name() string {
+	return `0=` + d.Kind + `_` + d.Version
+}
+
+// Contents returns the required contents of the declaration file.
+func (d Declaration) Contents() string {
+	return d.Kind + `_` + d.Version + "\n"
+}
+
+// Validate checks that contents are the required contents of the
+// declaration file. It returns ErrDeclarationCRLF or ErrDeclarationNoNewline
+// if the contents differ only in the line ending, and ErrDeclarationContents
+// otherwise.
+func (d Declaration) Validate(contents []byte) error {
+	expected := d.Kind + `_` + d.Version
+	switch string(contents) {
+	case expected + "\n":
+		return nil
+	case expected + "\r\n":
+		return ErrDeclarationCRLF
+	case expected:
+		return ErrDeclarationNoNewline
+	}
+	return ErrDeclarationContents
+}
+
+// ParseDeclaration parses a declaration file name, returning its kind
+// (DeclObject or DeclRoot) and OCFL spec version. ok is false if name isn't
+// an OCFL declaration. Versions aren't checked against the versions
+// implemented by this package.
+func ParseDeclaration(name string) (kind string, version string, ok bool) {
+	match := namasteRegexp.FindStringSubmatch(name)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// isObjectDeclaration returns true if name is an object declaration file
+// name, for any spec version.
+func isObjectDeclaration(name string) bool {
+	kind, _, ok := ParseDeclaration(name)
+	return ok && kind == DeclObject
+}
+
+// FindDeclaration returns the declaration among directory entries. It
+// returns an error wrapping ErrDeclarationNotFound if there are no
+// declarations, or ErrDeclarationMultiple if there is more than one.
+func FindDeclaration(entries []fs.DirEntry) (Declaration, error) {
+	var found []Declaration
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if kind, version, ok := ParseDeclaration(e.Name()); ok {
+			found = append(found, Declaration{Kind: kind, Version: version})
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Declaration{}, ErrDeclarationNotFound
+	case 1:
+		return found[0], nil
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Name() < found[j].Name()
+	})
+	return Declaration{}, &AmbiguousDeclarationErr{Declarations: found}
+}
+
+// AmbiguousDeclarationErr is returned if a directory has more than one
+// NAMASTE declaration, of any kind. It wraps ErrDeclarationMultiple.
+type AmbiguousDeclarationErr struct {
+	Declarations []Declaration // sorted by name
+}
+
+func (e *AmbiguousDeclarationErr) Error() string {
+	names := make([]string, len(e.Declarations))
+	kinds := map[string]bool{}
+	for i, d := range e.Declarations {
+		names[i] = d.Name()
+		kinds[d.Kind] = true
+	}
+	msg := fmt.Sprintf("%s: %s", ErrDeclarationMultiple, strings.Join(names, ", "))
+	if kinds[DeclObject] && kinds[DeclRoot] {
+		msg += " (both object and storage root declarations)"
+	}
+	return msg
+}
+
+func (e *AmbiguousDeclarationErr) Unwrap() error {
+	return ErrDeclarationMultiple
+}
+
+// object returns the object declaration for spec version v.
+func (e *AmbiguousDeclarationErr) object(v string) (Declaration, bool) {
+	for _, d := range e.Declarations {
+		if d.Kind == DeclObject && d.Version == v {
+			return d, true
+		}
+	}
+	return Declaration{}, false
+}
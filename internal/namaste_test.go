@@ -0,0 +1,176 @@
+package internal_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestParseDeclaration(t *testing.T) {
+	table := map[string]internal.Declaration{
+		`0=ocfl_object_1.0`:  {Kind: internal.DeclObject, Version: `1.0`},
+		`0=ocfl_object_1.1`:  {Kind: internal.DeclObject, Version: `1.1`},
+		`0=ocfl_1.0`:         {Kind: internal.DeclRoot, Version: `1.0`},
+		`0=ocfl_2.12`:        {Kind: internal.DeclRoot, Version: `2.12`},
+		`0=ocfl_object_1`:    {},
+		`0=ocfl_object_`:     {},
+		`1=ocfl_object_1.0`:  {},
+		`0=bagit_1.0`:        {},
+		`0=ocfl_object_1.0 `: {},
+	}
+	for name, expected := range table {
+		kind, version, ok := internal.ParseDeclaration(name)
+		if ok != (expected.Kind != "") {
+			t.Errorf("%q: expected ok=%v", name, !ok)
+			continue
+		}
+		if kind != expected.Kind || version != expected.Version {
+			t.Errorf("%q: got %s %s", name, kind, version)
+		}
+		if ok && expected.Name() != name {
+			t.Errorf("expected Name() %s, got %s", name, expected.Name())
+		}
+	}
+}
+
+func TestDeclarationValidate(t *testing.T) {
+	decl := internal.Declaration{Kind: internal.DeclObject, Version: `1.0`}
+	table := map[string]error{
+		"ocfl_object_1.0\n":   nil,
+		"ocfl_object_1.0\r\n": internal.ErrDeclarationCRLF,
+		"ocfl_object_1.0":     internal.ErrDeclarationNoNewline,
+		"ocfl_object_1.1\n":   internal.ErrDeclarationContents,
+		"ocfl_1.0\n":          internal.ErrDeclarationContents,
+		"":                    internal.ErrDeclarationContents,
+	}
+	for contents, expected := range table {
+		if err := decl.Validate([]byte(contents)); !errors.Is(err, expected) {
+			t.Errorf("%q: expected %v, got %v", contents, expected, err)
+		}
+	}
+	if decl.Contents() != "ocfl_object_1.0\n" {
+		t.Errorf("unexpected contents: %q", decl.Contents())
+	}
+}
+
+func TestFindDeclaration(t *testing.T) {
+	entries := func(names ...string) []fs.DirEntry {
+		fsys := fstest.MapFS{}
+		for _, n := range names {
+			fsys[n] = &fstest.MapFile{}
+		}
+		// a directory with a declaration name is ignored
+		fsys[`0=ocfl_1.0/file`] = &fstest.MapFile{}
+		items, err := fs.ReadDir(fsys, `.`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return items
+	}
+	decl, err := internal.FindDeclaration(entries(`0=ocfl_object_1.1`, `inventory.json`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decl.Kind != internal.DeclObject || decl.Version != `1.1` {
+		t.Errorf("unexpected declaration: %+v", decl)
+	}
+	if _, err := internal.FindDeclaration(entries(`inventory.json`)); !errors.Is(err, internal.ErrDeclarationNotFound) {
+		t.Errorf("expected ErrDeclarationNotFound, got %v", err)
+	}
+	_, err = internal.FindDeclaration(entries(`0=ocfl_object_1.0`, `0=ocfl_object_1.1`))
+	if !errors.Is(err, internal.ErrDeclarationMultiple) {
+		t.Errorf("expected ErrDeclarationMultiple, got %v", err)
+	}
+}
+
+func TestAmbiguousDeclaration(t *testing.T) {
+	table := map[string]struct {
+		extra    []string // declarations added to the fixture
+		hint     string
+		expected []string // declarations in the error
+		opens    bool     // opens with hint
+	}{
+		`two object versions`: {
+			extra:    []string{`0=ocfl_object_1.1`},
+			expected: []string{`0=ocfl_object_1.0`, `0=ocfl_object_1.1`},
+		},
+		`object and root`: {
+			extra:    []string{`0=ocfl_1.0`},
+			expected: []string{`0=ocfl_1.0`, `0=ocfl_object_1.0`},
+		},
+		`three declarations`: {
+			extra:    []string{`0=ocfl_1.0`, `0=ocfl_object_1.1`},
+			expected: []string{`0=ocfl_1.0`, `0=ocfl_object_1.0`, `0=ocfl_object_1.1`},
+		},
+		`hint`: {
+			extra:    []string{`0=ocfl_1.0`, `0=ocfl_object_1.1`},
+			hint:     `1.0`,
+			expected: []string{`0=ocfl_1.0`, `0=ocfl_object_1.0`, `0=ocfl_object_1.1`},
+			opens:    true,
+		},
+		`hint not found`: {
+			extra:    []string{`0=ocfl_1.0`},
+			hint:     `1.1`,
+			expected: []string{`0=ocfl_1.0`, `0=ocfl_object_1.0`},
+		},
+	}
+	for name, tcase := range table {
+		t.Run(name, func(t *testing.T) {
+			fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+			for _, n := range tcase.extra {
+				kind, v, _ := internal.ParseDeclaration(n)
+				decl := internal.Declaration{Kind: kind, Version: v}
+				fsys[n] = &fstest.MapFile{Data: []byte(decl.Contents())}
+			}
+			var opts []internal.ObjectOption
+			if tcase.hint != "" {
+				opts = append(opts, internal.WithSpecVersion(tcase.hint))
+			}
+			checkErr := func(err error) {
+				t.Helper()
+				var ambiguous *internal.AmbiguousDeclarationErr
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("expected AmbiguousDeclarationErr, got %v", err)
+				}
+				if !errors.Is(err, internal.ErrDeclarationMultiple) {
+					t.Error("expected error to wrap ErrDeclarationMultiple")
+				}
+				var names []string
+				for _, d := range ambiguous.Declarations {
+					names = append(names, d.Name())
+				}
+				if strings.Join(names, ",") != strings.Join(tcase.expected, ",") {
+					t.Errorf("expected declarations %v, got %v", tcase.expected, names)
+				}
+			}
+			obj, err := internal.NewObjectReader(fsys, opts...)
+			if tcase.opens {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if obj.SpecVersion() != tcase.hint {
+					t.Errorf("expected spec version %s, got %s", tcase.hint, obj.SpecVersion())
+				}
+				if _, err := obj.LogicalFS(); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				checkErr(err)
+			}
+			// validation reports E003 with the declarations
+			result := internal.ValidateObject(fsys, opts...)
+			if len(result.Fatal()) != 1 {
+				t.Fatalf("expected one error, got %v", result.Fatal())
+			}
+			if code := result.Fatal()[0].Code(); code != `E003` {
+				t.Errorf("expected E003, got %s", code)
+			}
+			checkErr(result.Fatal()[0])
+		})
+	}
+}
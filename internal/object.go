@@ -2,43 +2,119 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
-	"io/ioutil"
 	"path"
 	"strings"
 )
 
-type objectRoot struct{ fs.FS }
+type objectRoot struct {
+	fs.FS
+	permissive bool            // see WithPermissiveParsing
+	skipSchema bool            // see WithoutSchemaValidation
+	limits     InventoryLimits // see WithInventoryLimits
+	ctx        context.Context // used by Open, if set
+}
+
+var (
+	// ErrDeclarationCRLF indicates the object declaration ends with a CRLF
+	// instead of a newline.
+	ErrDeclarationCRLF = errors.New(`declaration has CRLF line ending`)
+	// ErrDeclarationNoNewline indicates the object declaration is missing
+	// its trailing newline.
+	ErrDeclarationNoNewline = errors.New(`declaration missing trailing newline`)
+	// ErrInventoryBOM indicates an inventory file begins with a UTF-8 byte
+	// order mark.
+	ErrInventoryBOM = errors.New(`inventory begins with UTF-8 BOM`)
+)
 
-// readDeclaration reads and validates the declaration file.
-// If an error is returned, it is a ValidationErr
-func (root *objectRoot) readDeclaration() error {
-	f, err := root.Open(objectDeclarationFile)
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// parseTolerable returns true if err is a parsing error that is accepted
+// with WithPermissiveParsing.
+func parseTolerable(err error) bool {
+	return errors.Is(err, ErrDeclarationCRLF) ||
+		errors.Is(err, ErrDeclarationNoNewline) ||
+		errors.Is(err, ErrInventoryBOM)
+}
+
+// readDeclaration reads and validates the declaration file, returning the
+// declared OCFL spec version. If the object root can be listed, it must have
+// exactly one declaration. Otherwise, the declaration for the implemented
+// spec version is used. If an error is returned, it is a ValidationErr. If
+// the declaration's only problem is a CRLF line ending or a missing trailing
+// newline, the version is returned with the error (see parseTolerable). If
+// the root has several declarations including an object declaration for spec
+// version hint, hint is returned with an error wrapping an
+// *AmbiguousDeclarationErr.
+func (root *objectRoot) readDeclaration(hint string) (string, error) {
+	decl, ambiguous, err := root.findDeclaration(hint)
+	if err != nil {
+		return "", &validationErr{err: err, code: &ErrE003}
+	}
+	f, err := root.Open(decl.Name())
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			return &validationErr{
-				err:  fmt.Errorf(`OCFL object declaration not found`),
-				code: &ErrE003,
-			}
+			err = fmt.Errorf(`OCFL object declaration not found: %w`, fs.ErrNotExist)
 		}
-		return &validationErr{err: err, code: &ErrE003}
+		return "", &validationErr{err: err, code: &ErrE003}
 	}
 	defer f.Close()
-	decl, err := io.ReadAll(f)
+	contents, err := io.ReadAll(f)
 	if err != nil {
-		return err
+		return "", err
+	}
+	err = decl.Validate(contents)
+	switch {
+	case err == nil:
+		if ambiguous != nil {
+			return decl.Version, &validationErr{err: ambiguous, code: &ErrE003}
+		}
+		return decl.Version, nil
+	case parseTolerable(err):
+		return decl.Version, &validationErr{err: err, code: &ErrE007}
+	}
+	return "", &validationErr{
+		err:  fmt.Errorf(`OCFL object %w`, err),
+		code: &ErrE007,
+	}
+}
+
+// findDeclaration returns the object declaration in the object root. If the
+// root can't be listed (not all backends support it), the declaration for
+// the implemented spec version is returned. If the root has more than one
+// declaration, an *AmbiguousDeclarationErr is returned, unless one is an
+// object declaration for spec version hint. In that case, it is returned
+// along with the *AmbiguousDeclarationErr.
+func (root *objectRoot) findDeclaration(hint string) (Declaration, *AmbiguousDeclarationErr, error) {
+	items, err := fs.ReadDir(root, `.`)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return Declaration{}, nil, err
+		}
+		return Declaration{Kind: DeclObject, Version: ocflVersion}, nil, nil
 	}
-	if string(decl) != objectDeclaration+"\n" {
-		return &validationErr{
-			err:  errors.New(`OCFL object declaration has invalid text contents`),
-			code: &ErrE007,
+	decl, err := FindDeclaration(items)
+	var ambiguous *AmbiguousDeclarationErr
+	switch {
+	case errors.As(err, &ambiguous):
+		if d, ok := ambiguous.object(hint); ok {
+			return d, ambiguous, nil
 		}
+		return Declaration{}, nil, ambiguous
+	case err != nil:
+		return Declaration{}, nil, fmt.Errorf(`OCFL object declaration not found: %w`, fs.ErrNotExist)
+	case decl.Kind != DeclObject:
+		return Declaration{}, nil, fmt.Errorf(`OCFL object declaration not found (found %s): %w`, decl.Name(), fs.ErrNotExist)
+	case decl.Version != ocflVersion && !specNewer(decl.Version):
+		// declarations for earlier spec versions aren't supported
+		return Declaration{}, nil, fmt.Errorf(`OCFL object declaration not found (found %s): %w`, decl.Name(), fs.ErrNotExist)
 	}
-	return nil
+	return decl, nil, nil
 }
 
 // reads and parses the inventory.json file in dir.
@@ -49,24 +125,65 @@ func (root *objectRoot) readInventory(dir string, validate bool) (*Inventory, er
 		return nil, err
 	}
 	defer file.Close()
+	limits := root.limits.withDefaults()
+	invBytes, err := limits.readAll(file, path)
+	if err != nil {
+		return nil, err
+	}
+	// the inventory digest is always calculated from invBytes, with the BOM.
+	jsonBytes := invBytes
+	if bytes.HasPrefix(invBytes, utf8BOM) {
+		if !root.permissive {
+			return nil, &validationErr{
+				err:  fmt.Errorf("%w: %s", ErrInventoryBOM, path),
+				code: &ErrE033,
+			}
+		}
+		jsonBytes = invBytes[len(utf8BOM):]
+	}
+	if err := limits.checkPaths(jsonBytes, path); err != nil {
+		return nil, err
+	}
+	lowMemory := limits.lowMemory(len(invBytes))
 	if !validate {
 		// inventory digest not set!
-		return ReadInventory(file)
+		inv, err := ReadInventory(bytes.NewReader(jsonBytes))
+		if err != nil {
+			return nil, err
+		}
+		inv.bom = len(jsonBytes) != len(invBytes)
+		inv.lowMemory = lowMemory
+		if !lowMemory {
+			inv.raw = invBytes
+		}
+		return inv, nil
 	}
 	// all validations performed
-	invBytes, err := ioutil.ReadAll(file)
-	if err != nil {
-		return nil, err
+	if err := validUTF8(jsonBytes); err != nil {
+		return nil, &validationErr{err: err, code: &ErrE033}
 	}
 	// json schema validation
-	result := validateInventoryBytes(invBytes)
-	if !result.Valid() {
-		return nil, &result
+	if !root.skipSchema && !lowMemory {
+		result := validateInventoryBytes(jsonBytes)
+		if !result.Valid() {
+			// report structural problems with whatever could be parsed
+			if inv, err := ReadInventory(bytes.NewReader(jsonBytes)); err == nil {
+				if err := inv.Validate(); err != nil {
+					result.AddFatal(err, nil)
+				}
+			}
+			return nil, &result
+		}
 	}
-	inv, err := ReadInventory(bytes.NewReader(invBytes))
+	inv, err := ReadInventory(bytes.NewReader(jsonBytes))
 	if err != nil {
 		return nil, err
 	}
+	inv.bom = len(jsonBytes) != len(invBytes)
+	inv.lowMemory = lowMemory
+	if !lowMemory {
+		inv.raw = invBytes
+	}
 	// consistency b/w manifest and version states
 	err = inv.Validate()
 	if err != nil {
@@ -111,6 +228,11 @@ func (root *objectRoot) readInventorySidecar(dir string, alg string) (string, er
 			code: &ErrE058,
 		}
 	}
+	return sidecarDigest(cont)
+}
+
+// sidecarDigest returns the digest in the contents of an inventory sidecar
+func sidecarDigest(cont []byte) (string, error) {
 	sidecar := string(cont)
 	offset := strings.Index(string(sidecar), " ")
 	if offset < 0 || !digestRegexp.MatchString(sidecar[:offset]) {
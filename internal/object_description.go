@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+// DescriptionSchemaVersion is the version of the ObjectDescription JSON
+// schema. It changes if fields are removed or their meaning changes; fields
+// may be added without changing it.
+const DescriptionSchemaVersion = `1`
+
+// ObjectDescription is a self-contained description of an object's versions,
+// files, and fixity, returned by Describe. It is intended for systems that
+// don't read OCFL inventories: its JSON encoding is stable (see
+// DescriptionSchemaVersion).
+type ObjectDescription struct {
+	SchemaVersion   string               `json:"schema_version"`
+	ID              string               `json:"id"`
+	SpecVersion     string               `json:"spec_version"`
+	DigestAlgorithm string               `json:"digest_algorithm"`
+	Head            string               `json:"head"`
+	Versions        []VersionDescription `json:"versions"`
+	Fixity          []FixityDescription  `json:"fixity,omitempty"`
+	Extensions      []string             `json:"extensions,omitempty"`
+	Events          []Event              `json:"events,omitempty"`
+}
+
+// VersionDescription describes a version in an ObjectDescription.
+type VersionDescription struct {
+	Version string            `json:"version"`
+	Created time.Time         `json:"created"`
+	User    *User             `json:"user,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Files   []FileDescription `json:"files"`
+}
+
+// FileDescription describes a logical file in a VersionDescription.
+type FileDescription struct {
+	Path   string `json:"path"` // logical path
+	Digest string `json:"digest"`
+	// Size is the content file's size. It is omitted if sizes weren't
+	// requested or the content file couldn't be found.
+	Size *int64 `json:"size,omitempty"`
+	// ContentPath is the first of the content paths for the digest, sorted.
+	ContentPath string `json:"content_path"`
+	// Introduced is the version with ContentPath
+	Introduced string `json:"introduced"`
+}
+
+// FixityDescription summarizes an inventory fixity block in an
+// ObjectDescription.
+type FixityDescription struct {
+	Algorithm string `json:"algorithm"`
+	Entries   int    `json:"entries"` // number of content paths
+}
+
+// DescribeOption is used to configure Describe
+type DescribeOption func(*describeOptions)
+
+type describeOptions struct {
+	noSizes bool
+	history bool
+}
+
+// WithoutDescribeSizes configures Describe to omit file sizes. Sizes require
+// a stat for each content file.
+func WithoutDescribeSizes() DescribeOption {
+	return func(opts *describeOptions) {
+		opts.noSizes = true
+	}
+}
+
+// WithDescribeHistory configures Describe to include the object's event
+// history (see History).
+func WithDescribeHistory() DescribeOption {
+	return func(opts *describeOptions) {
+		opts.history = true
+	}
+}
+
+// Describe returns an ObjectDescription for the object. Versions are in
+// version order, and files are sorted by logical path. Content files are
+// not read. An error is returned if ctx is canceled or a version state is
+// invalid.
+func (obj *ObjectReader) Describe(ctx context.Context, opts ...DescribeOption) (*ObjectDescription, error) {
+	var conf describeOptions
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	obj = obj.withContext(ctx)
+	inv := obj.inventory
+	desc := &ObjectDescription{
+		SchemaVersion:   DescriptionSchemaVersion,
+		ID:              inv.ID,
+		SpecVersion:     obj.spec,
+		DigestAlgorithm: inv.DigestAlgorithm,
+		Head:            inv.Head,
+		Versions:        []VersionDescription{},
+	}
+	vnames := inv.VersionDirs()
+	sortVersions(vnames)
+	sizes := map[string]*int64{} // by content path
+	for _, vname := range vnames {
+		ver := inv.Versions[vname]
+		vdesc := VersionDescription{
+			Version: vname,
+			Created: ver.Created,
+			Message: ver.Message,
+			Files:   []FileDescription{},
+		}
+		if ver.User != (User{}) {
+			user := ver.User
+			vdesc.User = &user
+		}
+		paths, err := ver.State.Paths()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range sortedKeys(paths) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			file := FileDescription{Path: p, Digest: paths[p]}
+			contentPaths := inv.contentCandidates(file.Digest)
+			if len(contentPaths) > 0 {
+				file.ContentPath = contentPaths[0]
+				file.Introduced, _ = inv.VersionOfContentPath(file.ContentPath)
+			}
+			if !conf.noSizes && file.ContentPath != "" {
+				size, ok := sizes[file.ContentPath]
+				if !ok {
+					size, err = obj.contentFileSize(file.ContentPath)
+					if err != nil {
+						return nil, err
+					}
+					sizes[file.ContentPath] = size
+				}
+				file.Size = size
+			}
+			vdesc.Files = append(vdesc.Files, file)
+		}
+		desc.Versions = append(desc.Versions, vdesc)
+	}
+	for alg, dm := range inv.Fixity {
+		var entries int
+		for _, paths := range dm {
+			entries += len(paths)
+		}
+		desc.Fixity = append(desc.Fixity, FixityDescription{Algorithm: alg, Entries: entries})
+	}
+	sort.Slice(desc.Fixity, func(i, j int) bool {
+		return desc.Fixity[i].Algorithm < desc.Fixity[j].Algorithm
+	})
+	exts, err := fs.ReadDir(obj.root, extensionsDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range exts {
+		if e.IsDir() {
+			desc.Extensions = append(desc.Extensions, e.Name())
+		}
+	}
+	if conf.history {
+		desc.Events = obj.History()
+	}
+	return desc, nil
+}
+
+// contentFileSize returns the size of the content file at p, or nil if it
+// doesn't exist.
+func (obj *ObjectReader) contentFileSize(p string) (*int64, error) {
+	info, err := fs.Stat(obj.root, path.Clean(p))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	size := info.Size()
+	return &size, nil
+}
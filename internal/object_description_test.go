@@ -0,0 +1,186 @@
+package internal_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/srerickson/ocfl/internal"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares data to the named file in testdata, or replaces the
+// file with -update.
+func checkGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(golden, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, data) {
+		t.Errorf("output doesn't match %s (run with -update if the change is intended):\n%s", golden, data)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	obj, err := internal.NewObjectReader(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := obj.Describe(context.Background(), internal.WithDescribeHistory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.MarshalIndent(desc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, `describe_spec-ex-full.json`, append(data, '\n'))
+
+	// without sizes
+	desc, err = obj.Describe(context.Background(), internal.WithoutDescribeSizes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(desc.Events) > 0 {
+		t.Error("expected no events without WithDescribeHistory")
+	}
+	for _, v := range desc.Versions {
+		for _, f := range v.Files {
+			if f.Size != nil {
+				t.Errorf("%s %s: expected no size", v.Version, f.Path)
+			}
+		}
+	}
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := obj.Describe(ctx); err == nil {
+		t.Error("expected an error with canceled context")
+	}
+}
+
+// content stored in more than one version is described by its content path
+// in the earliest version.
+func TestDescribeContentPathOrder(t *testing.T) {
+	gen := ocfltest.NewObject(t, ocfltest.WithVersions(10), ocfltest.WithFiles(1))
+	fsys := gen.FS
+	const v2Path = `v2/content/dir-0/file-2-0.dat`
+	const v10Path = `v10/content/copy.dat`
+	fsys[v10Path] = &fstest.MapFile{Data: fsys[v2Path].Data}
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	digest := sha512Hex(fsys[v2Path].Data)
+	manifest := inv["manifest"].(map[string]interface{})
+	manifest[digest] = append(manifest[digest].([]interface{}), v10Path)
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v10`, inv)
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := obj.Describe(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := obj.ResolvedPath(`v10/dir-0/file-2-0.dat`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != v2Path {
+		t.Fatalf("expected ResolvedPath to return %s, got %s", v2Path, resolved)
+	}
+	for _, v := range desc.Versions {
+		for _, f := range v.Files {
+			if f.Digest != digest {
+				continue
+			}
+			if f.ContentPath != v2Path || f.Introduced != `v2` {
+				t.Errorf("%s %s: expected content path %s introduced in v2, got %s in %s",
+					v.Version, f.Path, v2Path, f.ContentPath, f.Introduced)
+			}
+		}
+	}
+}
+
+// TestDescribeSchema pins the JSON schema of ObjectDescription, so changes to
+// the description's structure are deliberate.
+func TestDescribeSchema(t *testing.T) {
+	schema := jsonSchema(reflect.TypeOf(internal.ObjectDescription{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "OCFL object description, schema version " + internal.DescriptionSchemaVersion
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, `describe_schema.json`, append(data, '\n'))
+}
+
+// jsonSchema returns a JSON schema for values of type typ, as encoded by
+// encoding/json. Fields without omitempty are required.
+func jsonSchema(typ reflect.Type) map[string]interface{} {
+	if typ == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return jsonSchema(typ.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": jsonSchema(typ.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchema(typ.Elem())}
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")
+			if tag[0] == "-" || field.PkgPath != "" {
+				continue
+			}
+			name := tag[0]
+			if name == "" {
+				name = field.Name
+			}
+			props[name] = jsonSchema(field.Type)
+			if len(tag) < 2 || tag[1] != "omitempty" {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	panic("no schema for " + typ.String())
+}
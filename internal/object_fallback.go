@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// WithInventoryFallback configures NewObjectReader to recover objects whose
+// root inventory is missing or can't be parsed. Version directories are
+// searched, from the highest version down, for an inventory that passes
+// validation. If one is found, it is used as the object's inventory and the
+// ObjectReader is marked as degraded.
+func WithInventoryFallback() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.inventoryFallback = true
+	}
+}
+
+// Degraded returns true if the ObjectReader was opened using a version
+// inventory because the root inventory could not be read. See
+// WithInventoryFallback.
+func (obj *ObjectReader) Degraded() bool {
+	return obj.degradedErr != nil
+}
+
+// DegradedErr returns the error encountered reading the root inventory of a
+// degraded ObjectReader. It returns nil if the ObjectReader is not degraded.
+func (obj *ObjectReader) DegradedErr() error {
+	return obj.degradedErr
+}
+
+// fallbackInventory returns the validated inventory from the highest version
+// directory that has one. The version directory's inventory must describe
+// itself as the head version.
+func (root *objectRoot) fallbackInventory() (*Inventory, error) {
+	items, err := fs.ReadDir(root, `.`)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, i := range items {
+		if !i.IsDir() {
+			continue
+		}
+		if _, _, err := versionParse(i.Name()); err == nil {
+			versions = append(versions, i.Name())
+		}
+	}
+	sortVersions(versions)
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		inv, err := root.readInventory(v, true)
+		if err != nil {
+			continue
+		}
+		if inv.Head != v {
+			continue
+		}
+		return inv, nil
+	}
+	return nil, errors.New(`no valid version inventory found`)
+}
+
+// openFallback sets the object's inventory using fallbackInventory. invErr is
+// the error reading the root inventory.
+func (obj *ObjectReader) openFallback(invErr error) error {
+	inv, err := obj.root.fallbackInventory()
+	if err != nil {
+		return fmt.Errorf("%w (inventory fallback: %s)", invErr, err.Error())
+	}
+	obj.inventory = inv
+	obj.degradedErr = invErr
+	return nil
+}
@@ -0,0 +1,81 @@
+package internal_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestInventoryFallback(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	delete(fsys, `inventory.json`)
+	delete(fsys, `inventory.json.sha512`)
+	// without fallback
+	_, err := internal.NewObjectReader(fsys)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got %v", err)
+	}
+	obj, err := internal.NewObjectReader(fsys, internal.WithInventoryFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obj.Degraded() {
+		t.Fatal("expected object to be degraded")
+	}
+	if !errors.Is(obj.DegradedErr(), fs.ErrNotExist) {
+		t.Errorf("expected DegradedErr to be ErrNotExist, got %v", obj.DegradedErr())
+	}
+	rec, err := obj.InventoryAt(``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Missing {
+		t.Error("expected root inventory to be missing")
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(logical, "v2/foo/bar.xml", "v3/empty2.txt"); err != nil {
+		t.Error(err)
+	}
+	// invalid head inventory: fallback to previous version
+	fsys[`v3/inventory.json`] = &fstest.MapFile{Data: []byte(`{}`)}
+	obj, err = internal.NewObjectReader(fsys, internal.WithInventoryFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logical, err = obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(logical, "v2/foo/bar.xml"); err != nil {
+		t.Error(err)
+	}
+	if _, err := fs.Stat(logical, "v3"); err == nil {
+		t.Error("expected v3 to be absent")
+	}
+	// no valid inventories
+	for _, v := range []string{`v1`, `v2`} {
+		fsys[v+`/inventory.json`] = &fstest.MapFile{Data: []byte(`{}`)}
+	}
+	_, err = internal.NewObjectReader(fsys, internal.WithInventoryFallback())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestInventoryFallbackNotDegraded(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys, internal.WithInventoryFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Degraded() {
+		t.Error("expected object not to be degraded")
+	}
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ErrObjectPathInvalid indicates an object path passed to ObjectFS that
+// isn't a valid, relative path below the storage root. It is wrapped by
+// *ObjectPathErr.
+var ErrObjectPathInvalid = errors.New(`invalid object path`)
+
+// ObjectPathErr is returned by ObjectFS for invalid object paths. It wraps
+// ErrObjectPathInvalid.
+type ObjectPathErr struct {
+	Path   string
+	Reason string
+}
+
+func (e *ObjectPathErr) Error() string {
+	return fmt.Sprintf("%s %q: %s", ErrObjectPathInvalid, e.Path, e.Reason)
+}
+
+func (e *ObjectPathErr) Unwrap() error {
+	return ErrObjectPathInvalid
+}
+
+// ObjectFS returns an FS for the object in directory objPath of rootFS. A
+// trailing slash in objPath is ignored. An *ObjectPathErr is returned if
+// objPath is empty, absolute, has empty, "." or ".." elements, or includes
+// backslashes or NUL bytes. An error wrapping ErrDeclarationNotFound is
+// returned if the directory doesn't have an object declaration. The
+// returned FS rejects names that aren't valid fs.FS paths, or that include
+// backslashes or NUL bytes, before they are passed to rootFS, so files
+// outside objPath can't be opened through it.
+func ObjectFS(rootFS fs.FS, objPath string) (fs.FS, error) {
+	dir := strings.TrimSuffix(objPath, "/")
+	if err := objectPathErr(dir); err != "" {
+		return nil, &ObjectPathErr{Path: objPath, Reason: err}
+	}
+	fsys := &objectFS{base: rootFS, dir: dir}
+	entries, err := fs.ReadDir(fsys, `.`)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Type().IsRegular() && isObjectDeclaration(e.Name()) {
+			return fsys, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", objPath, ErrDeclarationNotFound)
+}
+
+// objectPathErr returns the reason p isn't a valid object path, or "".
+func objectPathErr(p string) string {
+	switch {
+	case p == "":
+		return "empty path"
+	case strings.ContainsRune(p, 0):
+		return "contains NUL byte"
+	case strings.Contains(p, `\`):
+		return "contains backslash"
+	case strings.HasPrefix(p, "/"):
+		return "absolute path"
+	}
+	for _, elem := range strings.Split(p, "/") {
+		switch elem {
+		case "":
+			return "empty path element"
+		case ".", "..":
+			return fmt.Sprintf("%q path element", elem)
+		}
+	}
+	if !fs.ValidPath(p) {
+		return "not a valid path"
+	}
+	return ""
+}
+
+// objectFS is an FS for an object directory in a storage root FS
+type objectFS struct {
+	base fs.FS
+	dir  string
+}
+
+var _ OpenContextFS = (*objectFS)(nil)
+var _ CapabilitiesFS = (*objectFS)(nil)
+
+// name returns the name in the base FS, or an error if name isn't valid.
+func (fsys *objectFS) name(op string, name string) (string, error) {
+	if !fs.ValidPath(name) || strings.ContainsRune(name, 0) || strings.Contains(name, `\`) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return path.Join(fsys.dir, name), nil
+}
+
+func (fsys *objectFS) Open(name string) (fs.File, error) {
+	full, err := fsys.name(`open`, name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fsys.base.Open(full)
+	return f, fsys.pathErr(name, err)
+}
+
+// OpenContext implements OpenContextFS. If the base FS doesn't implement it,
+// ctx is only checked before opening the file.
+func (fsys *objectFS) OpenContext(ctx context.Context, name string) (fs.File, error) {
+	full, err := fsys.name(`open`, name)
+	if err != nil {
+		return nil, err
+	}
+	ctxFS, ok := fsys.base.(OpenContextFS)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, &fs.PathError{Op: `open`, Path: name, Err: err}
+		}
+		return fsys.Open(name)
+	}
+	f, err := ctxFS.OpenContext(ctx, full)
+	return f, fsys.pathErr(name, err)
+}
+
+// Capabilities implements CapabilitiesFS using the base FS's capabilities.
+// MaxKeyLength is reduced by the length of the object's directory.
+func (fsys *objectFS) Capabilities() Capabilities {
+	caps := CapabilitiesOf(fsys.base)
+	if caps.MaxKeyLength > 0 {
+		caps.MaxKeyLength -= len(fsys.dir) + 1
+	}
+	return caps
+}
+
+// pathErr replaces the path of *fs.PathErrors from the base FS with name,
+// so paths outside the object aren't exposed.
+func (fsys *objectFS) pathErr(name string, err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return &fs.PathError{Op: pathErr.Op, Path: name, Err: pathErr.Err}
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package internal_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// storageRootDir writes objects a/obj1 and a/obj2 and a file outside them
+// to a temporary directory.
+func storageRootDir(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	write := func(name string, data []byte) {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, obj := range []string{`a/obj1`, `a/obj2`} {
+		for name, file := range loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`)) {
+			write(obj+"/"+name, file.Data)
+		}
+	}
+	write(`0=ocfl_1.0`, []byte("ocfl_1.0\n"))
+	write(`secret.txt`, []byte("secret"))
+	write(`a/secret.txt`, []byte("secret"))
+	return root
+}
+
+func TestObjectFS(t *testing.T) {
+	rootFS := os.DirFS(storageRootDir(t))
+	fsys, err := internal.ObjectFS(rootFS, `a/obj1/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := internal.ValidateObject(fsys); !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	// not an object
+	for _, p := range []string{`a`, `a/missing`} {
+		if _, err := internal.ObjectFS(rootFS, p); err == nil {
+			t.Errorf("expected an error for %q", p)
+		}
+	}
+	if _, err := internal.ObjectFS(rootFS, `a`); !errors.Is(err, internal.ErrDeclarationNotFound) {
+		t.Errorf("expected ErrDeclarationNotFound, got %v", err)
+	}
+}
+
+func TestObjectFSInvalidPath(t *testing.T) {
+	rootFS := os.DirFS(storageRootDir(t))
+	for _, p := range []string{
+		``,
+		`/`,
+		`/a/obj1`,
+		`.`,
+		`..`,
+		`../a/obj1`,
+		`a/../a/obj1`,
+		`a/obj2/..`,
+		`a/obj1/../obj2`,
+		`./a/obj1`,
+		`a/./obj1`,
+		`a//obj1`,
+		`a\obj1`,
+		`..\a\obj1`,
+		`C:\a\obj1`,
+		"a/obj1\x00",
+		"a/obj1\x00/../obj2",
+	} {
+		_, err := internal.ObjectFS(rootFS, p)
+		var pathErr *internal.ObjectPathErr
+		if !errors.As(err, &pathErr) || !errors.Is(err, internal.ErrObjectPathInvalid) {
+			t.Errorf("ObjectFS(%q): expected *ObjectPathErr, got %v", p, err)
+		}
+	}
+}
+
+func TestObjectFSTraversal(t *testing.T) {
+	fsys, err := internal.ObjectFS(os.DirFS(storageRootDir(t)), `a/obj1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		`..`,
+		`../secret.txt`,
+		`../../secret.txt`,
+		`../obj2/inventory.json`,
+		`../obj2/0=ocfl_object_1.0`,
+		`v1/../../obj2/inventory.json`,
+		`v1/../../../secret.txt`,
+		`/secret.txt`,
+		`/etc/passwd`,
+		`./inventory.json`,
+		`v1/./content`,
+		`v1//content`,
+		`inventory.json/`,
+		``,
+		`..\secret.txt`,
+		`..\obj2\inventory.json`,
+		`v1\..\..\secret.txt`,
+		"inventory.json\x00",
+		"inventory.json\x00../secret.txt",
+		"\x00",
+	} {
+		f, err := fsys.Open(name)
+		if err == nil {
+			f.Close()
+			t.Errorf("Open(%q): expected an error", name)
+			continue
+		}
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Open(%q): expected fs.ErrInvalid, got %v", name, err)
+		}
+	}
+	// paths that look encoded are ordinary names in the object
+	for _, name := range []string{`%2e%2e/secret.txt`, `..%2fsecret.txt`} {
+		if _, err := fsys.Open(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Open(%q): expected fs.ErrNotExist, got %v", name, err)
+		}
+	}
+	if _, err := fs.ReadFile(fsys, `inventory.json`); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ErrInventoryMismatch indicates the root inventory and the head version's
+// inventory differ, or one of them doesn't match its sidecar. It is wrapped
+// by *InventoryMismatchErr.
+var ErrInventoryMismatch = errors.New(`root and head version inventories don't match`)
+
+// InventoryMismatchErr is returned by NewObjectReader with WithIntegrityCheck
+// if the root and head version inventories don't match. It wraps
+// ErrInventoryMismatch.
+type InventoryMismatchErr struct {
+	Head    string   // the root inventory's head version
+	Details []string // what differed
+}
+
+func (e *InventoryMismatchErr) Error() string {
+	return fmt.Sprintf("%s (head %s): %s", ErrInventoryMismatch, e.Head, strings.Join(e.Details, "; "))
+}
+
+func (e *InventoryMismatchErr) Unwrap() error {
+	return ErrInventoryMismatch
+}
+
+// WithIntegrityCheck configures NewObjectReader to check that the root
+// inventory is identical to the head version's inventory and that both
+// match their sidecars. If they don't, the object isn't opened and an
+// *InventoryMismatchErr is returned. Content files aren't read. It can't be
+// used with WithInventoryFallback.
+func WithIntegrityCheck() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.integrityCheck = true
+	}
+}
+
+// IntegrityReport describes inconsistencies in an object's root directory
+// that may result from an interrupted update.
+type IntegrityReport struct {
+	// UnreferencedVersions lists version directories that aren't referenced
+	// by the root inventory.
+	UnreferencedVersions []string
+	// HeadIncomplete is true if the root inventory's head version directory
+	// is missing, or if it has an inventory without a sidecar. A head version
+	// directory without an inventory isn't incomplete.
+	HeadIncomplete bool
+	// Leftovers lists other files and directories in the object root that
+	// shouldn't be there, such as temporary inventory files.
+	Leftovers []string
+}
+
+// Consistent returns true if the report found no problems
+func (r IntegrityReport) Consistent() bool {
+	return len(r.UnreferencedVersions) == 0 &&
+		!r.HeadIncomplete &&
+		len(r.Leftovers) == 0
+}
+
+// CheckIntegrity checks the object root for states associated with an
+// interrupted update: version directories not referenced by the root
+// inventory, a head version directory that is missing or incomplete, and
+// leftover files. It does not validate the object.
+func (obj *ObjectReader) CheckIntegrity(ctx context.Context) (IntegrityReport, error) {
+	var report IntegrityReport
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	items, err := fs.ReadDir(obj.root, `.`)
+	if err != nil {
+		return report, err
+	}
+	expectedFiles := map[string]bool{
+		inventoryFile:               true,
+		obj.inventory.SidecarFile(): true,
+	}
+	for _, i := range items {
+		name := i.Name()
+		if i.IsDir() {
+			if _, ok := obj.inventory.Versions[name]; ok || name == extensionsDir {
+				continue
+			}
+			if _, _, err := versionParse(name); err == nil {
+				report.UnreferencedVersions = append(report.UnreferencedVersions, name)
+				continue
+			}
+			report.Leftovers = append(report.Leftovers, name)
+			continue
+		}
+		if expectedFiles[name] || isObjectDeclaration(name) {
+			continue
+		}
+		report.Leftovers = append(report.Leftovers, name)
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	report.HeadIncomplete, err = obj.headIncomplete()
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// headIncomplete returns true if the head version directory is missing, or
+// if it has an inventory without a sidecar. A version directory without an
+// inventory is allowed.
+func (obj *ObjectReader) headIncomplete() (bool, error) {
+	head := obj.inventory.Head
+	_, err := fs.Stat(obj.root, path.Join(head, inventoryFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		_, err = fs.Stat(obj.root, head)
+		if errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+		return false, err
+	}
+	if err != nil {
+		return false, err
+	}
+	_, err = fs.Stat(obj.root, path.Join(head, obj.inventory.SidecarFile()))
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	return false, err
+}
+
+// checkInventories compares the root inventory with the head version's
+// inventory and checks both sidecars. It returns an *InventoryMismatchErr
+// if they don't match.
+func (obj *ObjectReader) checkInventories() error {
+	inv := obj.inventory
+	mismatch := &InventoryMismatchErr{Head: inv.Head}
+	if inv.Head == "" {
+		mismatch.Details = append(mismatch.Details, "no head version")
+		return mismatch
+	}
+	newH, err := newHash(inv.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
+	rootBytes, err := obj.InventoryBytes()
+	if err != nil {
+		return err
+	}
+	rootDigest := inventoryDigest{alg: inv.DigestAlgorithm}
+	headDigest := inventoryDigest{alg: inv.DigestAlgorithm}
+	h := newH()
+	h.Write(rootBytes)
+	rootDigest.sum = h.Sum(nil)
+	headBytes, err := fs.ReadFile(obj.root, path.Join(inv.Head, inventoryFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		h := newH()
+		h.Write(headBytes)
+		headDigest.sum = h.Sum(nil)
+	}
+	diffs, err := obj.root.compareHeadInventory(rootDigest, inv.Head, headDigest)
+	if err != nil {
+		return err
+	}
+	for _, diff := range diffs {
+		mismatch.Details = append(mismatch.Details, diff.err.Error())
+	}
+	if len(mismatch.Details) > 0 {
+		return mismatch
+	}
+	return nil
+}
+
+// inventoryDigest is the digest of an inventory file
+type inventoryDigest struct {
+	alg string
+	sum []byte // nil if the inventory is missing
+}
+
+// compareHeadInventory checks that the root inventory and sidecar are
+// identical to the inventory and sidecar in the head version directory,
+// and that each inventory matches its sidecar. rootDigest and headDigest
+// are the digests of the two inventories. It is used for validation and by
+// WithIntegrityCheck. Each difference is returned as a validationErr;
+// errors reading sidecars are returned as err.
+func (root *objectRoot) compareHeadInventory(rootDigest inventoryDigest, head string, headDigest inventoryDigest) ([]*validationErr, error) {
+	var diffs []*validationErr
+	addDiff := func(code *OCFLCodeErr, format string, a ...interface{}) {
+		diffs = append(diffs, &validationErr{err: fmt.Errorf(format, a...), code: code})
+	}
+	headInv := path.Join(head, inventoryFile)
+	switch {
+	case headDigest.sum == nil:
+		addDiff(&ErrE064, "%s is missing", headInv)
+	case rootDigest.alg != headDigest.alg || !bytes.Equal(rootDigest.sum, headDigest.sum):
+		addDiff(&ErrE064, "%s differs from %s: %s %s, %s %s", headInv, inventoryFile,
+			headDigest.alg, hex.EncodeToString(headDigest.sum),
+			rootDigest.alg, hex.EncodeToString(rootDigest.sum))
+	}
+	var sidecars [][]byte
+	for _, file := range []struct {
+		dir    string
+		digest inventoryDigest
+	}{{dir: `.`, digest: rootDigest}, {dir: head, digest: headDigest}} {
+		if file.digest.sum == nil {
+			continue
+		}
+		sidecarPath := path.Join(file.dir, inventoryFile+"."+file.digest.alg)
+		cont, err := fs.ReadFile(root, sidecarPath)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+			addDiff(&ErrE058, "%s is missing", sidecarPath)
+			continue
+		}
+		sidecars = append(sidecars, cont)
+		sidecar, err := sidecarDigest(cont)
+		if err != nil {
+			addDiff(&ErrE061, "%s has invalid contents", sidecarPath)
+			continue
+		}
+		if !strings.EqualFold(sidecar, hex.EncodeToString(file.digest.sum)) {
+			addDiff(&ErrE060, "%s doesn't match %s", path.Join(file.dir, inventoryFile), sidecarPath)
+		}
+	}
+	if len(sidecars) == 2 && !bytes.Equal(sidecars[0], sidecars[1]) {
+		addDiff(&ErrE064, "%s differs from %s", path.Join(head, inventoryFile+"."+headDigest.alg),
+			inventoryFile+"."+rootDigest.alg)
+	}
+	return diffs, nil
+}
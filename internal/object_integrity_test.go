@@ -0,0 +1,142 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	ctx := context.Background()
+	obj, err := internal.NewObjectReader(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := obj.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() {
+		t.Errorf("expected consistent report, got %+v", report)
+	}
+
+	// interrupted update: new version directory not in root inventory, a
+	// missing head sidecar, and a temporary inventory file
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	fsys[`v4/content/new.txt`] = &fstest.MapFile{Data: []byte("new")}
+	fsys[`inventory.json.tmp`] = &fstest.MapFile{Data: []byte("{")}
+	delete(fsys, `v3/inventory.json.sha512`)
+	obj, err = internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = obj.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.UnreferencedVersions, []string{`v4`}) {
+		t.Errorf("expected unreferenced version v4, got %v", report.UnreferencedVersions)
+	}
+	if !report.HeadIncomplete {
+		t.Error("expected head to be incomplete")
+	}
+	if !reflect.DeepEqual(report.Leftovers, []string{`inventory.json.tmp`}) {
+		t.Errorf("expected leftover inventory.json.tmp, got %v", report.Leftovers)
+	}
+
+	// version directories without inventories are not incomplete
+	obj, err = internal.NewObjectReader(os.DirFS(filepath.Join(warnObjPath, `W010_no_version_inventory`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = obj.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() {
+		t.Errorf("expected consistent report, got %+v", report)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := obj.CheckIntegrity(canceled); err == nil {
+		t.Error("expected an error with canceled context")
+	}
+}
+
+func TestIntegrityCheck(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	if _, err := internal.NewObjectReader(fsys, internal.WithIntegrityCheck()); err != nil {
+		t.Fatal(err)
+	}
+	// WithInventoryFallback can't be used with WithIntegrityCheck
+	if _, err := internal.NewObjectReader(fsys, internal.WithIntegrityCheck(), internal.WithInventoryFallback()); err == nil {
+		t.Error("expected an error using WithIntegrityCheck and WithInventoryFallback")
+	}
+
+	// tampered head inventory with a matching sidecar
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`v3/inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	inv["id"] = "ark:tampered"
+	setInventory(t, fsys, `v3`, inv)
+	if _, err := internal.NewObjectReader(fsys); err != nil {
+		t.Fatal(err)
+	}
+	_, err := internal.NewObjectReader(fsys, internal.WithIntegrityCheck())
+	if !errors.Is(err, internal.ErrInventoryMismatch) {
+		t.Fatalf("expected ErrInventoryMismatch, got %v", err)
+	}
+	var mismatch *internal.InventoryMismatchErr
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected *InventoryMismatchErr, got %T", err)
+	}
+	expected := []string{
+		"v3/inventory.json differs from inventory.json: sha512 " + sha512Hex(fsys[`v3/inventory.json`].Data) +
+			", sha512 " + sha512Hex(fsys[`inventory.json`].Data),
+		"v3/inventory.json.sha512 differs from inventory.json.sha512",
+	}
+	if mismatch.Head != "v3" || !reflect.DeepEqual(mismatch.Details, expected) {
+		t.Errorf("unexpected mismatch: %+v", mismatch)
+	}
+
+	// head inventory doesn't match its sidecar
+	fsys = loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	fsys[`v3/inventory.json.sha512`] = &fstest.MapFile{Data: []byte(strings.Repeat("0", 128) + " inventory.json\n")}
+	delete(fsys, `inventory.json.sha512`)
+	_, err = internal.NewObjectReader(fsys, internal.WithIntegrityCheck())
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected *InventoryMismatchErr, got %v", err)
+	}
+	expected = []string{
+		"inventory.json.sha512 is missing",
+		"v3/inventory.json doesn't match v3/inventory.json.sha512",
+	}
+	if !reflect.DeepEqual(mismatch.Details, expected) {
+		t.Errorf("unexpected mismatch details: %v", mismatch.Details)
+	}
+
+	// malformed head sidecar
+	fsys = loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	fsys[`v3/inventory.json.sha512`] = &fstest.MapFile{Data: []byte("not a digest\n")}
+	_, err = internal.NewObjectReader(fsys, internal.WithIntegrityCheck())
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected *InventoryMismatchErr, got %v", err)
+	}
+	expected = []string{
+		"v3/inventory.json.sha512 has invalid contents",
+		"v3/inventory.json.sha512 differs from inventory.json.sha512",
+	}
+	if !reflect.DeepEqual(mismatch.Details, expected) {
+		t.Errorf("unexpected mismatch details: %v", mismatch.Details)
+	}
+}
@@ -0,0 +1,87 @@
+package internal_test
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestPermissiveParsing(t *testing.T) {
+	table := map[string]struct {
+		modify func(fstest.MapFS)
+		err    error
+	}{
+		`declaration CRLF`: {
+			modify: func(fsys fstest.MapFS) {
+				fsys[`0=ocfl_object_1.0`] = &fstest.MapFile{Data: []byte("ocfl_object_1.0\r\n")}
+			},
+			err: internal.ErrDeclarationCRLF,
+		},
+		`declaration no newline`: {
+			modify: func(fsys fstest.MapFS) {
+				fsys[`0=ocfl_object_1.0`] = &fstest.MapFile{Data: []byte("ocfl_object_1.0")}
+			},
+			err: internal.ErrDeclarationNoNewline,
+		},
+		`inventory BOM`: {
+			modify: func(fsys fstest.MapFS) {
+				// sidecar digests are of the file with the BOM
+				for _, v := range []string{`.`, `v1`} {
+					name := filepath.ToSlash(filepath.Join(v, `inventory.json`))
+					data := append([]byte{0xEF, 0xBB, 0xBF}, fsys[name].Data...)
+					fsys[name] = &fstest.MapFile{Data: data}
+					sidecar := name + `.sha512`
+					fsys[sidecar] = &fstest.MapFile{Data: []byte(sha512Hex(data) + " inventory.json\n")}
+				}
+			},
+			err: internal.ErrInventoryBOM,
+		},
+	}
+	for name, test := range table {
+		t.Run(name, func(t *testing.T) {
+			fsys := loadFixture(t, filepath.Join(goodObjPath, `minimal_one_version_one_file`))
+			test.modify(fsys)
+			// strict
+			_, err := internal.NewObjectReader(fsys)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			var vErr internal.ValidationErr
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected a ValidationErr, got %v", err)
+			}
+			result := internal.ValidateObject(fsys)
+			if result.Valid() {
+				t.Error("expected strict validation to fail")
+			}
+			// permissive
+			obj, err := internal.NewObjectReader(fsys, internal.WithPermissiveParsing())
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = obj.Validate()
+			if !result.Valid() {
+				t.Fatal(result.Fatal())
+			}
+			var found bool
+			for _, w := range result.Warning() {
+				if errors.Is(w, test.err) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected warning for %v, got %v", test.err, result.Warning())
+			}
+		})
+	}
+}
+
+func sha512Hex(data []byte) string {
+	sum := sha512.Sum512(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -1,47 +1,195 @@
 package internal
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"hash"
 	"io/fs"
-	"path"
-
-	"github.com/srerickson/checksum"
+	"time"
 )
 
-// ObjectReader represents a readable OCFL Object
+// ObjectReader represents a readable OCFL Object. An ObjectReader and its
+// inventory are not modified after NewObjectReader returns, so its methods
+// are safe for concurrent use. Validation works on a copy of the reader.
 type ObjectReader struct {
 	root      objectRoot // root fs
 	inventory *Inventory // inventory.json
 	logical   fs.FS
+	spec      string // declared OCFL spec version
+	opts      objectOptions
+	// error reading root inventory if opened with fallback
+	degradedErr error
+	// tolerated declaration error (see WithPermissiveParsing)
+	declarationErr error
+	// multiple declarations, if opened with WithSpecVersion
+	ambiguousErr error
+}
+
+// ObjectOption is used to configure NewObjectReader
+type ObjectOption func(*objectOptions)
+
+type objectOptions struct {
+	lenientSpec        bool
+	permissive         bool
+	skipSchema         bool
+	inventoryFallback  bool
+	noInventoryCache   bool
+	eventSink          EventSink
+	metrics            Metrics
+	profile            *Profile
+	checkpoint         Checkpointer
+	checkpointFiles    int
+	checkpointInterval time.Duration
+	specHint           string
+	limits             InventoryLimits
+	largestFirst       bool
+	integrityCheck     bool
+}
+
+// WithLenientSpec allows NewObjectReader to open objects that declare an OCFL
+// spec version newer than the one implemented by this package. The object
+// may be read but not validated.
+func WithLenientSpec() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.lenientSpec = true
+	}
+}
+
+// WithSpecVersion sets the OCFL spec version used to choose the object
+// declaration if the object root has more than one. Without it,
+// NewObjectReader returns an error wrapping *AmbiguousDeclarationErr for
+// these objects. The object is opened if it has an object declaration for
+// spec version v, but it isn't valid.
+func WithSpecVersion(v string) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.specHint = v
+	}
+}
+
+// WithPermissiveParsing allows NewObjectReader to open objects with an
+// object declaration that has a CRLF line ending or is missing its trailing
+// newline, and inventories that begin with a UTF-8 byte order mark. These
+// are reported as warnings during validation.
+func WithPermissiveParsing() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.permissive = true
+	}
+}
+
+// WithoutSchemaValidation disables JSON schema validation of inventories
+// during Validate. Schema validation is enabled by default; NewObjectReader
+// never uses it.
+func WithoutSchemaValidation() ObjectOption {
+	return func(opts *objectOptions) {
+		opts.skipSchema = true
+	}
 }
 
 // NewObjectReader returns a new ObjectReader with loaded inventory.
 // An error is returned only if:
 // 	- OCFL object declaration is missing or invalid.
 //  - The inventory is not be present or there was an error loading it
-func NewObjectReader(root fs.FS) (*ObjectReader, error) {
+//    (see WithInventoryFallback)
+//  - The object declares an unsupported OCFL spec version (see
+//    WithLenientSpec)
+//  - The root and head version inventories don't match (see
+//    WithIntegrityCheck)
+// Skeleton objects, with an inventory that has no versions, are opened so
+// they can be inspected, but they aren't valid (E008). Version-specific
+// methods return errors wrapping ErrVersionNotFound for these objects.
+func NewObjectReader(root fs.FS, opts ...ObjectOption) (*ObjectReader, error) {
+	return newObjectReader(context.Background(), root, opts...)
+}
+
+func newObjectReader(ctx context.Context, root fs.FS, opts ...ObjectOption) (*ObjectReader, error) {
 	if root == nil {
 		return nil, errors.New("cannot read nil FS")
 	}
-	obj := &ObjectReader{root: objectRoot{root}}
-	err := obj.root.readDeclaration()
-	if err != nil {
+	obj := &ObjectReader{}
+	for _, opt := range opts {
+		opt(&obj.opts)
+	}
+	if obj.opts.integrityCheck && obj.opts.inventoryFallback {
+		return nil, errors.New("WithIntegrityCheck can't be used with WithInventoryFallback")
+	}
+	obj.root = objectRoot{
+		FS:         root,
+		permissive: obj.opts.permissive,
+		skipSchema: obj.opts.skipSchema,
+		limits:     obj.opts.limits,
+		ctx:        ctx,
+	}
+	var err error
+	obj.spec, err = obj.root.readDeclaration(obj.opts.specHint)
+	var ambiguous *AmbiguousDeclarationErr
+	switch {
+	case err == nil:
+	case obj.spec != "" && errors.As(err, &ambiguous):
+		// reported as an error during validation
+		obj.ambiguousErr = err
+	case obj.opts.permissive && parseTolerable(err):
+		// reported as a warning (without E007) during validation
+		obj.declarationErr = errors.Unwrap(err)
+	default:
 		return nil, err
 	}
+	if specNewer(obj.spec) && !obj.opts.lenientSpec {
+		return nil, &SpecVersionErr{Version: obj.spec}
+	}
 	// don't validate inventory by default
 	obj.inventory, err = obj.root.readInventory(`.`, false)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			return nil, asValidationErr(err, &ErrE063)
+			err = asValidationErr(err, &ErrE063)
+		}
+		if !obj.opts.inventoryFallback {
+			return nil, err
+		}
+		if err := obj.openFallback(err); err != nil {
+			return nil, err
+		}
+	}
+	obj.root.resolveContentDirs(obj.inventory)
+	if v, err := obj.inventory.SpecVersion(); err == nil && specNewer(v) {
+		if !obj.opts.lenientSpec {
+			return nil, &SpecVersionErr{Version: v}
+		}
+		obj.spec = v
+	}
+	if obj.opts.integrityCheck {
+		if err := obj.checkInventories(); err != nil {
+			return nil, err
 		}
-		return nil, err
 	}
+	if obj.opts.noInventoryCache {
+		obj.inventory.raw = nil
+	}
+	// the context is only used while opening
+	obj.root.ctx = nil
 	return obj, nil
 }
 
+// SpecVersion returns the OCFL spec version declared by the object. If the
+// inventory type refers to a newer version than the object declaration,
+// the inventory's version is returned.
+func (obj *ObjectReader) SpecVersion() string {
+	return obj.spec
+}
+
+// checkSpec returns a *SpecVersionErr if the object's spec version is not
+// supported.
+func (obj *ObjectReader) checkSpec() error {
+	if specNewer(obj.spec) {
+		return &SpecVersionErr{Version: obj.spec}
+	}
+	return nil
+}
+
 func (obj *ObjectReader) LogicalFS() (fs.FS, error) {
 	files := make(map[string]string)
+	// alternate content paths, if the preferred path is missing
+	alts := make(map[string][]string)
 	// add every path from every version to obj.index
 	for vname, version := range obj.inventory.Versions {
 		paths, err := version.State.Paths()
@@ -49,11 +197,14 @@ func (obj *ObjectReader) LogicalFS() (fs.FS, error) {
 			return nil, asValidationErr(err, &ErrE095)
 		}
 		for p, digest := range paths {
-			targets := obj.inventory.Manifest[digest]
+			targets := obj.inventory.contentCandidates(digest)
 			if len(targets) == 0 {
 				return nil, fmt.Errorf("empty path list for digest: %s", digest)
 			}
 			files[vname+"/"+p] = targets[0]
+			if len(targets) > 1 {
+				alts[targets[0]] = targets[1:]
+			}
 		}
 	}
 	logical, err := NewAliasFS(obj.root, files)
@@ -63,38 +214,65 @@ func (obj *ObjectReader) LogicalFS() (fs.FS, error) {
 		}
 		return nil, asValidationErr(err, &ErrE095)
 	}
+	logical.alts = alts
 	return logical, nil
 }
 
 // Content returns DigestMap of all version contents
 func (obj *ObjectReader) Content() (DigestMap, error) {
-	var content DigestMap
-	alg := obj.inventory.DigestAlgorithm
-	newH, err := newHash(alg)
+	content, _, err := obj.digestContent(nil)
+	return content, err
+}
+
+// digestContent digests all version contents with the inventory's digest
+// algorithm and with each algorithm in fixity, reading each file once. It
+// returns the content DigestMap and the content files' digests for each
+// fixity algorithm.
+func (obj *ObjectReader) digestContent(fixity fixityMap) (DigestMap, fixityMap, error) {
+	algs, err := obj.contentAlgs(fixity)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	each := func(j checksum.Job, err error) error {
-		if err != nil {
-			return err
-		}
-		sum, err := j.SumString(alg)
-		if err != nil {
-			return err
-		}
-		return content.Add(sum, j.Path())
+	if obj.opts.largestFirst {
+		return obj.contentLargestFirst(algs, fixity)
+	}
+	var content PathIndex
+	digests := fixity.empty()
+	alg := obj.inventory.DigestAlgorithm
+	var sizes *sizeIndex
+	if obj.opts.metrics != nil {
+		sizes = &sizeIndex{}
+	}
+	each := func(name string, sums map[string]string, size int64) error {
+		obj.recordDigests(sums, size)
+		digests.add(name, sums)
+		return content.Add(sums[alg], name)
 	}
 	for v := range obj.inventory.Versions {
-		contentDir := path.Join(v, obj.inventory.ContentDirectory)
+		contentDir := obj.inventory.contentRef(v, "").Dir()
 		// contentDir may not exist - that's ok
-		err = checksum.Walk(obj.root, contentDir, each, checksum.WithAlg(alg, newH))
+		err := walkDigestsAlgs(obj.root.context(), obj.root, contentDir, algs, true, sizes, each)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return content.DigestMap(), digests, nil
+}
+
+// contentAlgs returns hash constructors for the inventory's digest
+// algorithm and the algorithms in fixity.
+func (obj *ObjectReader) contentAlgs(fixity fixityMap) (map[string]func() hash.Hash, error) {
+	algs := map[string]func() hash.Hash{}
+	names := []string{obj.inventory.DigestAlgorithm}
+	for alg := range fixity {
+		names = append(names, alg)
+	}
+	for _, alg := range names {
+		newH, err := newHash(alg)
 		if err != nil {
-			walkErr, _ := err.(*checksum.WalkErr)
-			if errors.Is(walkErr.WalkDirErr, fs.ErrNotExist) {
-				continue
-			}
 			return nil, err
 		}
+		algs[alg] = newH
 	}
-	return content, nil
+	return algs, nil
 }
@@ -1,6 +1,9 @@
 package internal_test
 
 import (
+	"bytes"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -33,3 +36,102 @@ func TestObjectReader(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// loadFixture returns a copy of the fixture directory as an fstest.MapFS that
+// can be modified by tests.
+func loadFixture(t *testing.T, dir string) fstest.MapFS {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		fsys[filepath.ToSlash(rel)] = &fstest.MapFile{Data: data}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fsys
+}
+
+func TestObjectReaderFutureSpec(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	delete(fsys, `0=ocfl_object_1.0`)
+	fsys[`0=ocfl_object_1.2`] = &fstest.MapFile{Data: []byte("ocfl_object_1.2\n")}
+	_, err := internal.NewObjectReader(fsys)
+	var specErr *internal.SpecVersionErr
+	if !errors.As(err, &specErr) {
+		t.Fatalf("expected SpecVersionErr, got %v", err)
+	}
+	if specErr.Version != `1.2` {
+		t.Errorf("expected version 1.2, got %s", specErr.Version)
+	}
+	obj, err := internal.NewObjectReader(fsys, internal.WithLenientSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.SpecVersion() != `1.2` {
+		t.Errorf("expected spec version 1.2, got %s", obj.SpecVersion())
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(logical, "v2/foo/bar.xml"); err != nil {
+		t.Error(err)
+	}
+	if _, err := obj.Content(); err != nil {
+		t.Error(err)
+	}
+	result := obj.Validate()
+	if result.Valid() {
+		t.Fatal("expected validation to fail")
+	}
+	if !errors.Is(result.Fatal()[0], internal.ErrUnsupportedSpecVersion) {
+		t.Errorf("expected ErrUnsupportedSpecVersion, got %v", result.Fatal()[0])
+	}
+
+	// invalid future declaration contents is still E007
+	fsys[`0=ocfl_object_1.2`] = &fstest.MapFile{Data: []byte("ocfl_object_1.0\n")}
+	_, err = internal.NewObjectReader(fsys, internal.WithLenientSpec())
+	var vErr internal.ValidationErr
+	if !errors.As(err, &vErr) || vErr.Code() != `E007` {
+		t.Errorf("expected E007, got %v", err)
+	}
+
+	// earlier declarations aren't supported
+	fsys = loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	delete(fsys, `0=ocfl_object_1.0`)
+	fsys[`0=ocfl_object_0.9`] = &fstest.MapFile{Data: []byte("ocfl_object_0.9\n")}
+	for _, opt := range []internal.ObjectOption{internal.WithLenientSpec(), internal.WithPermissiveParsing()} {
+		_, err = internal.NewObjectReader(fsys, opt)
+		if !errors.As(err, &vErr) || vErr.Code() != `E003` {
+			t.Errorf("expected E003, got %v", err)
+		}
+	}
+	result = internal.ValidateObject(fsys)
+	if result.Valid() || result.Fatal()[0].Code() != `E003` {
+		t.Errorf("expected E003, got %v", result.Fatal())
+	}
+
+	// future inventory type
+	fsys = loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	inv := bytes.Replace(fsys[`inventory.json`].Data, []byte(`/1.0/spec/`), []byte(`/1.1/spec/`), 1)
+	fsys[`inventory.json`] = &fstest.MapFile{Data: inv}
+	_, err = internal.NewObjectReader(fsys)
+	if !errors.As(err, &specErr) || specErr.Version != `1.1` {
+		t.Errorf("expected SpecVersionErr for 1.1, got %v", err)
+	}
+}
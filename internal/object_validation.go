@@ -1,41 +1,116 @@
 package internal
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io/fs"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/srerickson/checksum"
 	"github.com/srerickson/checksum/delta"
 )
 
+// Validate fully validates the object, including content fixity. The
+// validation profile set with WithProfile is applied to the result.
 func (obj *ObjectReader) Validate() ValidationResult {
+	return obj.ValidateContext(context.Background())
+}
+
+// validateAll runs all validation checks and records metrics and events.
+// The object's inventory is replaced with the fully validated root
+// inventory, so it must only be called on a copy of a shared ObjectReader.
+func (obj *ObjectReader) validateAll() ValidationResult {
+	start := time.Now()
+	profile := obj.opts.getProfile()
+	result := obj.validate()
+	for _, check := range profile.Checks {
+		for _, err := range check.Check(obj.inventory, obj.root) {
+			result.AddFatal(err, nil)
+		}
+	}
+	profile.apply(result)
+	result.setConformance()
+	m := obj.opts.getMetrics()
+	m.Observe(MetricValidationSeconds, nil, time.Since(start).Seconds())
+	for _, err := range result.Fatal() {
+		m.Add(MetricValidationErrors, map[string]string{"code": err.Code()}, 1)
+	}
+	valid := "true"
+	if !result.Valid() {
+		valid = "false"
+	}
+	m.Add(MetricObjectsValidated, map[string]string{"valid": valid}, 1)
+	obj.recordValidation(result)
+	return result
+}
+
+func (obj *ObjectReader) validate() *validationResult {
 	result := &validationResult{}
-	var err error
-	obj.inventory, err = obj.root.readInventory(`.`, true)
+	if err := obj.checkSpec(); err != nil {
+		return result.stop(err)
+	}
+	if obj.ambiguousErr != nil {
+		return result.stop(obj.ambiguousErr)
+	}
+	inv, err := obj.root.readInventory(`.`, true)
 	if err != nil {
-		return result.AddFatal(err, nil)
+		return result.stop(err)
+	}
+	obj.inventory = inv
+	obj.root.resolveContentDirs(inv)
+	result.setAlgorithm(`.`, inv.DigestAlgorithm)
+	if obj.opts.noInventoryCache {
+		inv.raw = nil
+	}
+	if obj.declarationErr != nil {
+		result.AddWarn(obj.declarationErr, nil)
+	}
+	if inv.bom {
+		result.AddWarn(fmt.Errorf("%w: %s", ErrInventoryBOM, inventoryFile), nil)
+	}
+	if inv.lowMemory {
+		result.AddWarn(lowMemoryErr(inventoryFile), nil)
+	}
+	if inv.DigestAlgorithm != SHA512 {
+		err := fmt.Errorf(`inventory uses %s`, inv.DigestAlgorithm)
+		result.AddWarn(err, &ErrW004)
+	}
+	for _, err := range inv.stringWarnings() {
+		result.AddWarn(err, nil)
+	}
+	for _, err := range inv.createdOrderErrs() {
+		result.AddWarn(err, &ErrCreatedOrder)
 	}
 	if err := obj.validateRoot(); err != nil {
-		return result.AddFatal(err, nil)
+		return result.stop(err)
+	}
+	if err := obj.validateExtensions(result); err != nil {
+		return result.stop(err)
 	}
+	decls := map[string]contentDirDecl{}
 	for v := range obj.inventory.Versions {
-		err := obj.validateVersionDir(v)
+		err := obj.validateVersionDir(v, decls, result)
 		if err != nil {
-			return result.AddFatal(err, nil)
+			return result.stop(err)
 		}
 	}
-	if err := obj.validateContent(); err != nil {
-		return result.AddFatal(err, nil)
+	obj.validateContentDirs(decls, result)
+	fixity, err := obj.fixityValues(result)
+	if err != nil {
+		return result.stop(err)
 	}
-	if err := obj.validateFixity(); err != nil {
-		return result.AddFatal(err, nil)
+	digests, err := obj.validateContent(fixity)
+	if err != nil {
+		return result.stop(err)
 	}
-	return nil
+	if err := validateFixity(fixity, digests); err != nil {
+		return result.stop(err)
+	}
+	return result
 }
 
 // validateRoot validates the object's root file structure. It checks
@@ -52,7 +127,7 @@ func (obj *ObjectReader) validateRoot() error {
 			objectDeclarationFile,
 		},
 		ReqDirs: obj.inventory.VersionDirs(),
-		OptDirs: []string{"extensions"},
+		OptDirs: []string{extensionsDir},
 	}
 	err = match.Match(items)
 	if err != nil {
@@ -89,7 +164,10 @@ func (obj *ObjectReader) validateRoot() error {
 	return nil
 }
 
-func (obj *ObjectReader) validateVersionDir(v string) error {
+// validateVersionDir validates the version directory v. Warnings are added to
+// result. The content directory declared by the version inventory is added
+// to decls.
+func (obj *ObjectReader) validateVersionDir(v string, decls map[string]contentDirDecl, result *validationResult) error {
 	items, err := fs.ReadDir(obj.root, v)
 	if err != nil {
 		return err
@@ -113,37 +191,63 @@ func (obj *ObjectReader) validateVersionDir(v string) error {
 		if err != nil {
 			return err
 		}
+		result.setAlgorithm(v, inv.DigestAlgorithm)
+		decls[v] = contentDirDecl{dir: inv.contentDirectory(), set: !inv.noContentDir}
+		if inv.bom {
+			err := fmt.Errorf("%w: %s", ErrInventoryBOM, path.Join(v, inventoryFile))
+			result.AddWarn(err, nil)
+		}
+		if inv.lowMemory {
+			result.AddWarn(lowMemoryErr(path.Join(v, inventoryFile)), nil)
+		}
+		obj.validateVersionHistory(v, inv, result)
 		if obj.inventory.Head == v {
-			// if this is the HEAD version, root inventory should match this inventory
-			if !bytes.Equal(obj.inventory.digest, inv.digest) {
-				err := fmt.Errorf(`root inventory doesn't match inventory for %s`, v)
-				return asValidationErr(err, &ErrE064)
+			rootDigest := inventoryDigest{alg: obj.inventory.DigestAlgorithm, sum: obj.inventory.digest}
+			headDigest := inventoryDigest{alg: inv.DigestAlgorithm, sum: inv.digest}
+			diffs, err := obj.root.compareHeadInventory(rootDigest, v, headDigest)
+			if err != nil {
+				return err
+			}
+			if len(diffs) > 0 {
+				return diffs[0]
 			}
 		}
 		return nil
 	}
-	// WARN no inventory
+	err = fmt.Errorf(`version directory has no inventory: %s`, v)
+	result.AddWarn(err, &ErrW010)
 	return nil
 }
 
-func (obj *ObjectReader) validateContent() error {
-	content, err := obj.Content()
+// validateContent digests content files and compares them to the manifest.
+// Files are also digested with the algorithms in fixity, and these digests
+// are returned.
+func (obj *ObjectReader) validateContent(fixity fixityMap) (fixityMap, error) {
+	var content DigestMap
+	var digests fixityMap
+	var err error
+	if obj.opts.checkpoint != nil {
+		content, digests, err = obj.checkpointContent(fixity)
+	} else {
+		content, digests, err = obj.digestContent(fixity)
+	}
 	if err != nil {
-		return err
+		// content couldn't be digested
+		return nil, asValidationErr(fmt.Errorf("reading content: %w", err), &ErrE092)
 	}
 	// path -> digest
 	allFiles, err := content.Paths()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// file and digests in content but not in manifest?
 	manifest, err := obj.inventory.Manifest.Normalize()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	paths, err := manifest.Paths()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	changes := delta.New(paths, allFiles)
 
@@ -155,16 +259,16 @@ func (obj *ObjectReader) validateContent() error {
 		}
 		err.RenamedFrom, err.RenamedTo = changes.Renamed()
 		if len(err.Modified) != 0 {
-			return asValidationErr(err, &ErrE092)
+			return nil, asValidationErr(err, &ErrE092)
 		}
-		return asValidationErr(fmt.Errorf("content includes files not in manifest"), &ErrE023)
+		return nil, asValidationErr(fmt.Errorf("content includes files not in manifest"), &ErrE023)
 	}
 	// TODO E024 - empty directories
-	return nil
+	return digests, nil
 }
 
 func (obj *ObjectReader) validateExtensionsDir() error {
-	items, err := fs.ReadDir(obj.root, "extensions")
+	items, err := fs.ReadDir(obj.root, extensionsDir)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil
@@ -182,58 +286,85 @@ func (obj *ObjectReader) validateExtensionsDir() error {
 	return nil
 }
 
-func (obj *ObjectReader) validateFixity() error {
-	if obj.inventory == nil {
-		return nil
+// fixityMap holds digests by fixity algorithm and content path
+type fixityMap map[string]map[string]string
+
+// empty returns a fixityMap with the same algorithms and no digests
+func (fm fixityMap) empty() fixityMap {
+	empty := make(fixityMap, len(fm))
+	for alg := range fm {
+		empty[alg] = map[string]string{}
 	}
-	if obj.inventory.Fixity == nil {
-		return nil
+	return empty
+}
+
+// add records the digests in sums for content path name, for algorithms in
+// fm.
+func (fm fixityMap) add(name string, sums map[string]string) {
+	for alg, digests := range fm {
+		digests[name] = sums[alg]
 	}
-	for alg, digestMap := range obj.inventory.Fixity {
-		digestMap, err := digestMap.Normalize()
-		if err != nil {
-			return asValidationErr(err, nil)
+}
+
+// has returns true if fm has a digest for content path name
+func (fm fixityMap) has(name string) bool {
+	for _, digests := range fm {
+		if _, ok := digests[name]; ok {
+			return true
 		}
-		hash, err := newHash(alg)
+	}
+	return false
+}
+
+// fixityValues returns the values in the inventory's fixity block.
+// Algorithms that aren't supported are skipped with a warning.
+func (obj *ObjectReader) fixityValues(result *validationResult) (fixityMap, error) {
+	if obj.inventory == nil || obj.inventory.Fixity == nil {
+		return nil, nil
+	}
+	fixity := fixityMap{}
+	for alg, dm := range obj.inventory.Fixity {
+		digestMap, err := dm.Normalize()
 		if err != nil {
-			return asValidationErr(err, nil)
+			return nil, asValidationErr(err, nil)
 		}
-		paths, err := digestMap.Paths()
-		if err != nil {
-			return asValidationErr(err, nil)
+		if _, err := newHash(alg); errors.Is(err, ErrUnsupportedAlgorithm) {
+			var entries int
+			for _, paths := range digestMap {
+				entries += len(paths)
+			}
+			result.AddWarn(fmt.Errorf("fixity not checked: %w: %s (%d entries)", ErrUnsupportedAlgorithm, alg, entries), nil)
+			continue
+		} else if err != nil {
+			return nil, asValidationErr(err, nil)
 		}
-		ctx, cancel := context.WithCancel(context.Background())
-		pipe, err := checksum.NewPipe(obj.root,
-			checksum.WithAlg(alg, hash),
-			checksum.WithCtx(ctx),
-		)
-		if err != nil {
-			cancel()
-			return asValidationErr(err, nil)
+		if fixity[alg], err = digestMap.Paths(); err != nil {
+			return nil, asValidationErr(err, nil)
 		}
-		go func() {
-			defer pipe.Close()
-			for path := range paths {
-				pipe.Add(path)
-			}
-		}()
-		for job := range pipe.Out() {
-			if err := job.Err(); err != nil {
-				cancel()
-				return asValidationErr(err, nil)
-			}
-			sum, err := job.SumString(alg)
-			if err != nil {
-				cancel()
-				return asValidationErr(err, nil)
+	}
+	return fixity, nil
+}
+
+// validateFixity checks the values in fixity against the content digests
+// calculated for each algorithm.
+func validateFixity(fixity fixityMap, digests fixityMap) error {
+	algs := make([]string, 0, len(fixity))
+	for alg := range fixity {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+	for _, alg := range algs {
+		for _, p := range sortedKeys(fixity[alg]) {
+			sum, ok := digests[alg][p]
+			if !ok {
+				err := fmt.Errorf("fixity check failed (%s): %s: %w", alg, p, fs.ErrNotExist)
+				return asValidationErr(err, &ErrE093)
 			}
-			if sum != paths[job.Path()] {
-				cancel()
-				err := fmt.Errorf("fixity check failed (%s): %s", alg, job.Path())
+			if sum != fixity[alg][p] {
+				err := fmt.Errorf("fixity check failed (%s): %s", alg, p)
 				return asValidationErr(err, &ErrE093)
 			}
 		}
-		cancel()
 	}
 	return nil
 }
@@ -2,9 +2,11 @@ package internal
 
 const (
 	ocflVersion           = "1.0"
-	objectDeclaration     = `ocfl_object_` + ocflVersion
-	objectDeclarationFile = `0=ocfl_object_` + ocflVersion
+	objectDeclarationName = `ocfl_object_`
+	objectDeclaration     = objectDeclarationName + ocflVersion
+	objectDeclarationFile = `0=` + objectDeclaration
 	inventoryFile         = `inventory.json`
+	extensionsDir         = `extensions`
 
 	// defaults
 	inventoryType   = `https://ocfl.io/1.0/spec/#inventory`
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PathHistory is the lifecycle of a logical path across an object's
+// versions, returned by Inventory.PathHistory and Inventory.AllPaths.
+type PathHistory struct {
+	Path string // logical path
+	// Ranges are the contiguous ranges of versions with the path, in version
+	// order. A new range begins if the path's digest changes.
+	Ranges []PathRange
+}
+
+// PathRange is a contiguous range of versions in which a logical path had
+// the same digest.
+type PathRange struct {
+	First  string // first version in the range
+	Last   string // last version in the range
+	Digest string
+	// RenamedFrom are logical paths in the version before First that had
+	// Digest and aren't in First. OCFL doesn't record renames, so the path
+	// may or may not have been renamed from them.
+	RenamedFrom []string
+}
+
+// PathHistory returns the history of logical path p. An error wrapping
+// ErrPathNotFound is returned if p isn't in any version state.
+func (inv *Inventory) PathHistory(p string) (PathHistory, error) {
+	vnames := inv.VersionDirs()
+	sortVersions(vnames)
+	digests := make([]string, len(vnames))
+	var found bool
+	for i, vname := range vnames {
+		digests[i], _ = inv.Versions[vname].State.PathDigest(p)
+		found = found || digests[i] != ""
+	}
+	if !found {
+		return PathHistory{}, fmt.Errorf("%w: %s", ErrPathNotFound, p)
+	}
+	return inv.pathHistory(p, vnames, digests), nil
+}
+
+// AllPaths calls fn with the history of every logical path in any version
+// state, in lexical order of paths. Paths are handled in batches: every
+// version state is scanned once per batch to collect the digests of the
+// batch's paths. Apart from the sorted list of unique paths, memory use is
+// bounded by the batch size, not by the number of paths in every version.
+// If fn returns an error, AllPaths stops and returns it. An error is also
+// returned if a version state is invalid; fn may already have been called
+// for earlier paths.
+func (inv *Inventory) AllPaths(fn func(PathHistory) error) error {
+	vnames := inv.VersionDirs()
+	sortVersions(vnames)
+	paths := inv.uniquePaths()
+	batch := len(paths) / allPathsPasses
+	if batch < allPathsMinBatch {
+		batch = allPathsMinBatch
+	}
+	for start := 0; start < len(paths); start += batch {
+		end := start + batch
+		if end > len(paths) {
+			end = len(paths)
+		}
+		chunk := paths[start:end]
+		// digests[j*len(vnames)+i] is the digest of chunk[j] in vnames[i]
+		digests := make([]string, len(chunk)*len(vnames))
+		for i, vname := range vnames {
+			for digest, ps := range inv.Versions[vname].State {
+				for _, p := range ps {
+					if p < chunk[0] || p > chunk[len(chunk)-1] {
+						continue
+					}
+					j := sort.SearchStrings(chunk, p)
+					if digests[j*len(vnames)+i] != "" {
+						return fmt.Errorf("version %s: %w", vname, &PathConflictErr{Path: p})
+					}
+					digests[j*len(vnames)+i] = digest
+				}
+			}
+		}
+		for j, p := range chunk {
+			history := inv.pathHistory(p, vnames, digests[j*len(vnames):(j+1)*len(vnames)])
+			if err := fn(history); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AllPaths batch sizes: batches have at least allPathsMinBatch paths, and
+// there are at most allPathsPasses batches.
+const (
+	allPathsMinBatch = 4096
+	allPathsPasses   = 8
+)
+
+// uniquePaths returns the logical paths in any version state, sorted.
+func (inv *Inventory) uniquePaths() []string {
+	unique := map[string]struct{}{}
+	for _, version := range inv.Versions {
+		for _, ps := range version.State {
+			for _, p := range ps {
+				unique[p] = struct{}{}
+			}
+		}
+	}
+	paths := make([]string, 0, len(unique))
+	for p := range unique {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// pathHistory returns the history of logical path p, given its digest in
+// each of the sorted versions vnames ("" if it is absent).
+func (inv *Inventory) pathHistory(p string, vnames []string, digests []string) PathHistory {
+	history := PathHistory{Path: p}
+	for i, digest := range digests {
+		if digest == "" {
+			continue
+		}
+		if i > 0 && strings.EqualFold(digest, digests[i-1]) {
+			history.Ranges[len(history.Ranges)-1].Last = vnames[i]
+			continue
+		}
+		r := PathRange{First: vnames[i], Last: vnames[i], Digest: digest}
+		if i > 0 {
+			prev, cur := inv.Versions[vnames[i-1]].State, inv.Versions[vnames[i]].State
+			for _, src := range prev[digest] {
+				if _, ok := cur.PathDigest(src); !ok {
+					r.RenamedFrom = append(r.RenamedFrom, src)
+				}
+			}
+			sort.Strings(r.RenamedFrom)
+		}
+		history.Ranges = append(history.Ranges, r)
+	}
+	return history
+}
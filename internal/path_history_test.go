@@ -0,0 +1,127 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// pathHistoryInventory has a.txt added in v1, modified in v3, deleted in v4,
+// and re-added in v6 with the content of old.txt from v5.
+func pathHistoryInventory() *internal.Inventory {
+	states := []internal.DigestMap{
+		{"d1": {"a.txt"}, "d2": {"b.txt"}},
+		{"d1": {"a.txt"}, "d2": {"b.txt"}},
+		{"d3": {"a.txt"}, "d2": {"b.txt"}},
+		{"d2": {"b.txt"}},
+		{"d2": {"b.txt"}, "d4": {"old.txt"}},
+		{"d2": {"b.txt"}, "d4": {"a.txt"}},
+	}
+	inv := &internal.Inventory{Head: "v6", Versions: map[string]*internal.Version{}}
+	for i, state := range states {
+		inv.Versions["v"+string(rune('1'+i))] = &internal.Version{State: state}
+	}
+	return inv
+}
+
+func TestPathHistory(t *testing.T) {
+	inv := pathHistoryInventory()
+	history, err := inv.PathHistory("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := internal.PathHistory{
+		Path: "a.txt",
+		Ranges: []internal.PathRange{
+			{First: "v1", Last: "v2", Digest: "d1"},
+			{First: "v3", Last: "v3", Digest: "d3"},
+			{First: "v6", Last: "v6", Digest: "d4", RenamedFrom: []string{"old.txt"}},
+		},
+	}
+	if !reflect.DeepEqual(history, expected) {
+		t.Errorf("expected %+v, got %+v", expected, history)
+	}
+	if _, err := inv.PathHistory("c.txt"); !errors.Is(err, internal.ErrPathNotFound) {
+		t.Errorf("expected ErrPathNotFound, got %v", err)
+	}
+}
+
+func TestAllPaths(t *testing.T) {
+	inv := pathHistoryInventory()
+	var histories []internal.PathHistory
+	err := inv.AllPaths(func(h internal.PathHistory) error {
+		histories = append(histories, h)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, h := range histories {
+		paths = append(paths, h.Path)
+		expected, err := inv.PathHistory(h.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(h, expected) {
+			t.Errorf("AllPaths and PathHistory differ for %s: %+v, %+v", h.Path, h, expected)
+		}
+	}
+	if !reflect.DeepEqual(paths, []string{"a.txt", "b.txt", "old.txt"}) {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+	// stop early
+	stop := errors.New("stop")
+	var calls int
+	err = inv.AllPaths(func(internal.PathHistory) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected AllPaths to stop after 1 call, got %d calls and %v", calls, err)
+	}
+}
+
+// AllPaths handles paths in batches
+func TestAllPathsBatches(t *testing.T) {
+	const n = 10000
+	inv := &internal.Inventory{Head: "v2", Versions: map[string]*internal.Version{
+		"v1": {State: internal.DigestMap{}},
+		"v2": {State: internal.DigestMap{}},
+	}}
+	for i := 0; i < n; i++ {
+		p := fmt.Sprintf("file-%05d.txt", i)
+		inv.Versions["v1"].State[fmt.Sprintf("d%d", i)] = []string{p}
+		if i%2 == 0 {
+			inv.Versions["v2"].State[fmt.Sprintf("d%d", i)] = []string{p}
+		}
+	}
+	var count int
+	err := inv.AllPaths(func(h internal.PathHistory) error {
+		expected := fmt.Sprintf("file-%05d.txt", count)
+		last := "v1"
+		if count%2 == 0 {
+			last = "v2"
+		}
+		if h.Path != expected || len(h.Ranges) != 1 || h.Ranges[0].Last != last {
+			t.Fatalf("unexpected history for path %d: %+v", count, h)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != n {
+		t.Errorf("expected %d paths, got %d", n, count)
+	}
+	// invalid state
+	inv.Versions["v2"].State["other"] = []string{"file-00000.txt"}
+	var conflict *internal.PathConflictErr
+	if err := inv.AllPaths(func(internal.PathHistory) error { return nil }); !errors.As(err, &conflict) {
+		t.Errorf("expected PathConflictErr, got %v", err)
+	}
+}
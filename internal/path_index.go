@@ -0,0 +1,91 @@
+package internal
+
+import "fmt"
+
+// PathIndex is a DigestMap with a reverse index of paths to digests, for
+// constant time lookups by path. DigestMap.Add scans every path in the map
+// to check for conflicts, so building a large DigestMap one path at a time
+// should use a PathIndex.
+type PathIndex struct {
+	digests DigestMap
+	paths   map[string]string
+}
+
+// NewPathIndex returns a PathIndex for a copy of dm. It returns a
+// *PathConflictErr if a path appears more than once in dm.
+func NewPathIndex(dm DigestMap) (*PathIndex, error) {
+	idx := &PathIndex{
+		digests: make(DigestMap, len(dm)),
+		paths:   make(map[string]string, len(dm)),
+	}
+	for d, paths := range dm {
+		for _, p := range paths {
+			if _, exists := idx.paths[p]; exists {
+				return nil, &PathConflictErr{Path: p}
+			}
+			idx.paths[p] = d
+		}
+		idx.digests[d] = append([]string{}, paths...)
+	}
+	return idx, nil
+}
+
+// PathDigest returns the digest for path p, and false if p isn't in the
+// index.
+func (idx *PathIndex) PathDigest(p string) (string, bool) {
+	d, ok := idx.paths[p]
+	return d, ok
+}
+
+// Add adds a digest->path entry. It has the same errors as DigestMap.Add.
+func (idx *PathIndex) Add(digest string, p string) error {
+	if !validPath(p) {
+		return &PathInvalidErr{p}
+	}
+	if idx.paths == nil {
+		idx.paths = map[string]string{}
+		idx.digests = DigestMap{}
+	}
+	if _, exists := idx.paths[p]; exists {
+		return &PathConflictErr{Path: p}
+	}
+	idx.paths[p] = digest
+	idx.digests[digest] = append(idx.digests[digest], p)
+	return nil
+}
+
+// Len returns the number of paths in the index.
+func (idx *PathIndex) Len() int {
+	return len(idx.paths)
+}
+
+// DigestMap returns the indexed DigestMap. It is shared with the index and
+// should not be modified directly.
+func (idx *PathIndex) DigestMap() DigestMap {
+	return idx.digests
+}
+
+// stateIndex returns a PathIndex for the state of version vname, for
+// lookups by logical path. For inventories read from JSON, it is built on
+// first use and cached, so the version's state must not be modified after
+// it is called.
+func (inv *Inventory) stateIndex(vname string) (*PathIndex, error) {
+	if inv.stateIndexes != nil {
+		if idx, ok := inv.stateIndexes.Load(vname); ok {
+			return idx.(*PathIndex), nil
+		}
+	}
+	version, ok := inv.Versions[vname]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrVersionNotFound, vname)
+	}
+	idx, err := NewPathIndex(version.State)
+	if err != nil {
+		return nil, err
+	}
+	if inv.stateIndexes == nil {
+		return idx, nil
+	}
+	cached, _ := inv.stateIndexes.LoadOrStore(vname, idx)
+	return cached.(*PathIndex), nil
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPathIndex(t *testing.T) {
+	dm := DigestMap{
+		"abc": {"a/file.txt", "b/file.txt"},
+		"def": {"c.txt"},
+	}
+	idx, err := NewPathIndex(dm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := idx.PathDigest("b/file.txt"); !ok || d != "abc" {
+		t.Errorf("unexpected PathDigest result: %s, %v", d, ok)
+	}
+	if _, ok := idx.PathDigest("missing.txt"); ok {
+		t.Error("expected PathDigest to return false for missing path")
+	}
+	if err := idx.Add("def", "d.txt"); err != nil {
+		t.Fatal(err)
+	}
+	var conflict *PathConflictErr
+	if err := idx.Add("abc", "c.txt"); !errors.As(err, &conflict) {
+		t.Errorf("expected PathConflictErr, got %v", err)
+	}
+	var invalid *PathInvalidErr
+	if err := idx.Add("abc", "../c.txt"); !errors.As(err, &invalid) {
+		t.Errorf("expected PathInvalidErr, got %v", err)
+	}
+	if idx.Len() != 4 {
+		t.Errorf("expected 4 paths, got %d", idx.Len())
+	}
+	if d, ok := idx.DigestMap().PathDigest("d.txt"); !ok || d != "def" {
+		t.Errorf("DigestMap not consistent with index: %s, %v", d, ok)
+	}
+	// the original isn't modified
+	if _, ok := dm.PathDigest("d.txt"); ok {
+		t.Error("NewPathIndex should copy the DigestMap")
+	}
+	if _, err := NewPathIndex(DigestMap{"abc": {"a.txt"}, "def": {"a.txt"}}); !errors.As(err, &conflict) {
+		t.Errorf("expected PathConflictErr, got %v", err)
+	}
+}
+
+func TestStateIndex(t *testing.T) {
+	inv, err := ReadInventory(strings.NewReader(`{
+		"id": "test",
+		"type": "https://ocfl.io/1.0/spec/#inventory",
+		"digestAlgorithm": "sha512",
+		"head": "v1",
+		"manifest": {"abc": ["v1/content/a.txt"]},
+		"versions": {"v1": {"created": "2020-01-01T00:00:00Z", "state": {"abc": ["a.txt", "b.txt"]}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := inv.stateIndex("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := idx.PathDigest("b.txt"); !ok || d != "abc" {
+		t.Errorf("unexpected PathDigest result: %s, %v", d, ok)
+	}
+	// the index is cached
+	if idx2, _ := inv.stateIndex("v1"); idx2 != idx {
+		t.Error("expected the cached index")
+	}
+	if _, err := inv.stateIndex("v2"); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+	// inventories that weren't read from JSON aren't cached
+	inv = &Inventory{Versions: map[string]*Version{"v1": {State: DigestMap{"abc": {"a.txt"}}}}}
+	if idx, err := inv.stateIndex("v1"); err != nil || idx.Len() != 1 {
+		t.Errorf("unexpected index: %v, %v", idx, err)
+	}
+}
+
+// BenchmarkPathIndex compares building a DigestMap one path at a time with
+// DigestMap.Add, which is quadratic, and PathIndex.Add.
+func BenchmarkPathIndex(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		if n <= 10000 {
+			b.Run(fmt.Sprintf("DigestMap-%d", n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					var dm DigestMap
+					for j := 0; j < n; j++ {
+						dm.Add(fmt.Sprintf("%0128x", j), fmt.Sprintf("v1/content/file-%d.txt", j))
+					}
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("PathIndex-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var idx PathIndex
+				for j := 0; j < n; j++ {
+					idx.Add(fmt.Sprintf("%0128x", j), fmt.Sprintf("v1/content/file-%d.txt", j))
+				}
+			}
+		})
+	}
+}
@@ -88,6 +88,16 @@ func (r *PathTree) Get(fname string) (interface{}, error) {
 	return val.Get(fname[offset+1:])
 }
 
+// Len returns the number of files in the tree, including files in
+// subdirectories.
+func (r *PathTree) Len() int {
+	n := len(r.Files)
+	for _, d := range r.Dirs {
+		n += d.Len()
+	}
+	return n
+}
+
 // //ImpB
 // type ImpB map[string]*Entry
 
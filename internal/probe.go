@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// Probe is the result of ProbeObject: basic information about an object
+// that can be read without parsing its inventory.
+type Probe struct {
+	Spec            string // declared OCFL spec version
+	DigestAlgorithm string // algorithm of the inventory sidecar
+	InventoryDigest string // inventory digest from the sidecar
+	root            objectRoot
+	scan            *inventoryHead
+}
+
+// inventoryHead holds fields scanned from the start of an inventory
+type inventoryHead struct {
+	ID              string
+	Head            string
+	DigestAlgorithm string
+}
+
+// ProbeObject reads the object declaration and inventory sidecar of the
+// object in directory dir of fsys. The inventory isn't read unless Head or
+// ID is called. An error wrapping fs.ErrNotExist is returned if there is no
+// object declaration or no inventory sidecar.
+func ProbeObject(ctx context.Context, fsys fs.FS, dir string) (*Probe, error) {
+	sub, err := fs.Sub(fsys, path.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+	probe := &Probe{root: objectRoot{FS: sub, ctx: ctx}}
+	probe.Spec, err = probe.root.readDeclaration("")
+	if err != nil && !parseTolerable(err) {
+		return nil, err
+	}
+	for _, alg := range digestAlgorithms {
+		digest, err := probe.root.readInventorySidecar(`.`, alg)
+		if err == nil {
+			probe.DigestAlgorithm = alg
+			probe.InventoryDigest = digest
+			return probe, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: inventory sidecar not found", fs.ErrNotExist)
+}
+
+// Head returns the head version from the object's inventory. The inventory
+// is scanned only until the id, head, and digestAlgorithm fields have been
+// read. If they come after the manifest and version states, those are
+// streamed through without being decoded.
+func (p *Probe) Head() (string, error) {
+	if err := p.scanInventory(); err != nil {
+		return "", err
+	}
+	return p.scan.Head, nil
+}
+
+// ID returns the object ID from the object's inventory. See Head.
+func (p *Probe) ID() (string, error) {
+	if err := p.scanInventory(); err != nil {
+		return "", err
+	}
+	return p.scan.ID, nil
+}
+
+func (p *Probe) scanInventory() error {
+	if p.scan != nil {
+		return nil
+	}
+	f, err := p.root.Open(inventoryFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	head, err := scanInventoryHead(f)
+	if err != nil {
+		return err
+	}
+	p.scan = head
+	return nil
+}
+
+// scanInventoryHead decodes the id, head, and digestAlgorithm fields of the
+// inventory JSON in r, stopping once all three have been read. Other values
+// are skipped without being decoded.
+func scanInventoryHead(r io.Reader) (*inventoryHead, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("inventory is not a JSON object: %w", scanErr(err))
+	}
+	head := &inventoryHead{}
+	fields := map[string]*string{
+		`id`:              &head.ID,
+		`head`:            &head.Head,
+		`digestAlgorithm`: &head.DigestAlgorithm,
+	}
+	for len(fields) > 0 && dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		if target, ok := fields[key]; ok {
+			if err := dec.Decode(target); err != nil {
+				return nil, fmt.Errorf("decoding %s: %w", key, err)
+			}
+			delete(fields, key)
+			continue
+		}
+		if err := skipValue(dec); err != nil {
+			return nil, err
+		}
+	}
+	if len(fields) > 0 {
+		return nil, errors.New(`inventory is missing id, head, or digestAlgorithm`)
+	}
+	return head, nil
+}
+
+// skipValue reads the next JSON value from dec without decoding it
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func scanErr(err error) error {
+	if err == nil {
+		return errors.New(`unexpected token`)
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+package internal_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// countFS counts bytes read from files
+type countFS struct {
+	fs.FS
+	read int64
+}
+
+func (fsys *countFS) Open(name string) (fs.File, error) {
+	f, err := fsys.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &countFile{File: f, fsys: fsys}, nil
+}
+
+type countFile struct {
+	fs.File
+	fsys *countFS
+}
+
+func (f *countFile) Read(p []byte) (int, error) {
+	n, err := f.File.Read(p)
+	f.fsys.read += int64(n)
+	return n, err
+}
+
+func TestProbeObject(t *testing.T) {
+	ctx := context.Background()
+	fixture := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	fsys := fstest.MapFS{}
+	for name, file := range fixture {
+		fsys[`objects/a/`+name] = file
+	}
+	probe, err := internal.ProbeObject(ctx, fsys, `objects/a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sidecar := strings.Fields(string(fixture[`inventory.json.sha512`].Data))[0]
+	if probe.Spec != `1.0` || probe.DigestAlgorithm != `sha512` || probe.InventoryDigest != sidecar {
+		t.Errorf("unexpected probe: %+v", probe)
+	}
+	head, err := probe.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != `v3` {
+		t.Errorf("expected head v3, got %s", head)
+	}
+	id, err := probe.ID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != `ark:/12345/bcd987` {
+		t.Errorf("unexpected id: %s", id)
+	}
+	// not an object
+	if _, err := internal.ProbeObject(ctx, fsys, `objects`); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+	if _, err := internal.ProbeObject(ctx, fsys, `objects/b`); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+	// head after a large manifest
+	inv := []byte(`{"manifest":{"abc":["v1/content/a.txt"]},"versions":{"v1":{"state":{"abc":["a.txt"]},"message":"{\"head\":\"v9\"}"}},"id":"x","digestAlgorithm":"sha512","head":"v1"}`)
+	fsys[`objects/a/inventory.json`] = &fstest.MapFile{Data: inv}
+	probe, err = internal.ProbeObject(ctx, fsys, `objects/a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head, err := probe.Head(); err != nil || head != `v1` {
+		t.Errorf("expected v1, got %s, %v", head, err)
+	}
+	// malformed
+	fsys[`objects/a/inventory.json`] = &fstest.MapFile{Data: []byte(`{"id": "x", "head": `)}
+	probe, err = internal.ProbeObject(ctx, fsys, `objects/a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := probe.Head(); err == nil {
+		t.Error("expected an error for a malformed inventory")
+	}
+}
+
+// BenchmarkProbeObject reports the bytes read from a 50MB inventory to get
+// the head.
+func BenchmarkProbeObject(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"id":"bench","type":"https://ocfl.io/1.0/spec/#inventory","digestAlgorithm":"sha512","head":"v1","manifest":{`)
+	for i := 0; buf.Len() < 25_000_000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `"%0128x":["v1/content/file-%d.txt"]`, i, i)
+	}
+	buf.WriteString(`},"versions":{"v1":{"created":"2020-01-01T00:00:00Z","state":{`)
+	for i := 0; buf.Len() < 50_000_000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `"%0128x":["file-%d.txt"]`, i, i)
+	}
+	buf.WriteString(`}}}}`)
+	fsys := &countFS{FS: fstest.MapFS{
+		`0=ocfl_object_1.0`:     &fstest.MapFile{Data: []byte("ocfl_object_1.0\n")},
+		`inventory.json`:        &fstest.MapFile{Data: buf.Bytes()},
+		`inventory.json.sha512`: &fstest.MapFile{Data: []byte(strings.Repeat("0", 128) + " inventory.json\n")},
+	}}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fsys.read = 0
+		probe, err := internal.ProbeObject(context.Background(), fsys, `.`)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := probe.Head(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(fsys.read), "bytes-read/op")
+	b.ReportMetric(float64(buf.Len()), "inventory-bytes")
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Names of the object root files in a RootSnapshot, used in RootChangeErr.
+const (
+	RootDeclaration = `declaration`
+	RootInventory   = `inventory`
+	RootSidecar     = `sidecar`
+)
+
+// ErrRootTampered indicates the object root files changed in a way that
+// isn't consistent with adding a new version.
+var ErrRootTampered = errors.New(`object root changed without a new version`)
+
+// RootSnapshot records sha512 digests of an object's declaration, root
+// inventory, and inventory sidecar at a point in time. It can be serialized
+// as JSON and checked later with ObjectReader.VerifySnapshot.
+type RootSnapshot struct {
+	Head        string `json:"head"`
+	Declaration string `json:"declaration"`
+	Inventory   string `json:"inventory"`
+	Sidecar     string `json:"sidecar"`
+}
+
+// RootChangeErr is returned by VerifySnapshot if any of the object root
+// files differ from the snapshot. If the change isn't Legitimate, it wraps
+// ErrRootTampered.
+type RootChangeErr struct {
+	Changed []string // RootDeclaration, RootInventory, or RootSidecar
+	// Legitimate is true if the change is consistent with adding a new
+	// version: only the inventory and sidecar changed, the head advanced,
+	// and the previous inventory is preserved in the previous head version
+	// directory.
+	Legitimate bool
+}
+
+func (e *RootChangeErr) Error() string {
+	msg := fmt.Sprintf("object root files changed: %s", strings.Join(e.Changed, ", "))
+	if !e.Legitimate {
+		return ErrRootTampered.Error() + ": " + msg
+	}
+	return msg
+}
+
+func (e *RootChangeErr) Unwrap() error {
+	if e.Legitimate {
+		return nil
+	}
+	return ErrRootTampered
+}
+
+// SnapshotRoot returns a RootSnapshot of the object's current root files.
+// The files are read from the object root, not from the ObjectReader's
+// loaded inventory.
+func (obj *ObjectReader) SnapshotRoot(ctx context.Context) (RootSnapshot, error) {
+	var snap RootSnapshot
+	inv, err := obj.root.readInventory(`.`, false)
+	if err != nil {
+		return snap, err
+	}
+	snap.Head = inv.Head
+	files := map[string]*string{
+		`0=` + objectDeclarationName + obj.spec: &snap.Declaration,
+		inventoryFile:                           &snap.Inventory,
+		inv.SidecarFile():                       &snap.Sidecar,
+	}
+	for name, digest := range files {
+		if err := ctx.Err(); err != nil {
+			return snap, err
+		}
+		*digest, err = obj.root.digestFile(name)
+		if err != nil {
+			return snap, err
+		}
+	}
+	return snap, nil
+}
+
+// VerifySnapshot compares the object's current root files to snap. It
+// returns nil if none of the files changed, or a *RootChangeErr describing
+// the change.
+func (obj *ObjectReader) VerifySnapshot(ctx context.Context, snap RootSnapshot) error {
+	current, err := obj.SnapshotRoot(ctx)
+	if err != nil {
+		return err
+	}
+	changeErr := &RootChangeErr{}
+	if current.Declaration != snap.Declaration {
+		changeErr.Changed = append(changeErr.Changed, RootDeclaration)
+	}
+	if current.Inventory != snap.Inventory {
+		changeErr.Changed = append(changeErr.Changed, RootInventory)
+	}
+	if current.Sidecar != snap.Sidecar {
+		changeErr.Changed = append(changeErr.Changed, RootSidecar)
+	}
+	if len(changeErr.Changed) == 0 {
+		return nil
+	}
+	if current.Declaration != snap.Declaration || !headAdvanced(snap.Head, current.Head) {
+		return changeErr
+	}
+	// the previous root inventory should be preserved in the previous head
+	prev, err := obj.root.digestFile(path.Join(snap.Head, inventoryFile))
+	if err != nil {
+		return changeErr
+	}
+	changeErr.Legitimate = prev == snap.Inventory
+	return changeErr
+}
+
+// headAdvanced returns true if version name cur is greater than prev.
+func headAdvanced(prev, cur string) bool {
+	p, _, err := versionParse(prev)
+	if err != nil {
+		return false
+	}
+	c, _, err := versionParse(cur)
+	if err != nil {
+		return false
+	}
+	return c > p
+}
+
+// digestFile returns the hex-encoded sha512 digest of the named file.
+func (root *objectRoot) digestFile(name string) (string, error) {
+	return root.digestFileAlg(name, SHA512)
+}
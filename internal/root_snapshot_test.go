@@ -0,0 +1,129 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// rollbackFixture returns the fixture as it was before the head version was
+// added, along with the fixture's current state.
+func rollbackFixture(t *testing.T, dir string) (fstest.MapFS, fstest.MapFS) {
+	full := loadFixture(t, dir)
+	old := loadFixture(t, dir)
+	for name := range old {
+		if len(name) > 3 && name[:3] == `v3/` {
+			delete(old, name)
+		}
+	}
+	old[`inventory.json`] = full[`v2/inventory.json`]
+	old[`inventory.json.sha512`] = full[`v2/inventory.json.sha512`]
+	return old, full
+}
+
+func TestRootSnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(goodObjPath, `updates_three_versions_one_file`)
+
+	t.Run("unchanged", func(t *testing.T) {
+		fsys := loadFixture(t, dir)
+		obj, err := internal.NewObjectReader(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		snap, err := obj.SnapshotRoot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// JSON round trip
+		b, err := json.Marshal(snap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var snap2 internal.RootSnapshot
+		if err := json.Unmarshal(b, &snap2); err != nil {
+			t.Fatal(err)
+		}
+		if err := obj.VerifySnapshot(ctx, snap2); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("new version", func(t *testing.T) {
+		fsys, full := rollbackFixture(t, dir)
+		obj, err := internal.NewObjectReader(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		snap, err := obj.SnapshotRoot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snap.Head != `v2` {
+			t.Fatalf("expected v2 head, got %s", snap.Head)
+		}
+		for name, file := range full {
+			fsys[name] = file
+		}
+		err = obj.VerifySnapshot(ctx, snap)
+		var changeErr *internal.RootChangeErr
+		if !errors.As(err, &changeErr) {
+			t.Fatalf("expected RootChangeErr, got %v", err)
+		}
+		if !changeErr.Legitimate || errors.Is(err, internal.ErrRootTampered) {
+			t.Errorf("expected legitimate change, got %v", err)
+		}
+		if len(changeErr.Changed) != 2 {
+			t.Errorf("expected inventory and sidecar to change, got %v", changeErr.Changed)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		fsys := loadFixture(t, dir)
+		obj, err := internal.NewObjectReader(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		snap, err := obj.SnapshotRoot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inv := append(fsys[`inventory.json`].Data, '\n')
+		fsys[`inventory.json`] = &fstest.MapFile{Data: inv}
+		fsys[`inventory.json.sha512`] = &fstest.MapFile{Data: []byte(sha512Hex(inv) + " inventory.json\n")}
+		err = obj.VerifySnapshot(ctx, snap)
+		if !errors.Is(err, internal.ErrRootTampered) {
+			t.Fatalf("expected ErrRootTampered, got %v", err)
+		}
+		var changeErr *internal.RootChangeErr
+		errors.As(err, &changeErr)
+		if len(changeErr.Changed) != 2 || changeErr.Changed[0] != internal.RootInventory {
+			t.Errorf("unexpected changes: %v", changeErr.Changed)
+		}
+	})
+
+	t.Run("new version, old inventory replaced", func(t *testing.T) {
+		fsys, full := rollbackFixture(t, dir)
+		obj, err := internal.NewObjectReader(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		snap, err := obj.SnapshotRoot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, file := range full {
+			fsys[name] = file
+		}
+		fsys[`v2/inventory.json`] = &fstest.MapFile{Data: []byte(`{}`)}
+		err = obj.VerifySnapshot(ctx, snap)
+		if !errors.Is(err, internal.ErrRootTampered) {
+			t.Fatalf("expected ErrRootTampered, got %v", err)
+		}
+	})
+}
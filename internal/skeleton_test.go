@@ -0,0 +1,84 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// skeleton objects have a root inventory without versions
+func TestSkeletonObject(t *testing.T) {
+	ctx := context.Background()
+	fsys := os.DirFS(filepath.Join(badObjPath, `E008_E036_no_versions_no_head`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// validation
+	result := obj.Validate()
+	var foundE008 bool
+	for _, err := range result.Fatal() {
+		if err.Code() == internal.ErrE008.Code {
+			foundE008 = true
+		}
+	}
+	if !foundE008 {
+		t.Errorf("expected E008, got %v", result.Fatal())
+	}
+
+	// inspection
+	desc, err := obj.Describe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	descJSON, err := json.Marshal(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(descJSON, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if vers, ok := decoded["versions"].([]interface{}); !ok || len(vers) != 0 {
+		t.Errorf("expected empty versions list, got %v", decoded["versions"])
+	}
+	if history := obj.History(); len(history) != 0 {
+		t.Errorf("expected no history, got %v", history)
+	}
+	content, err := obj.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) != 0 {
+		t.Errorf("expected no content, got %v", content)
+	}
+	logical, err := obj.LogicalFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := fs.ReadDir(logical, `.`); err != nil || len(entries) != 0 {
+		t.Errorf("expected empty logical FS, got %v, %v", entries, err)
+	}
+
+	// version-specific methods
+	if _, err := obj.ListFiles(`v1`); !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("ListFiles: expected ErrVersionNotFound, got %v", err)
+	}
+	if _, err := obj.VersionStats(`v1`); !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("VersionStats: expected ErrVersionNotFound, got %v", err)
+	}
+	if _, err := obj.InventoryAt(`v1`); !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("InventoryAt: expected ErrVersionNotFound, got %v", err)
+	}
+	if _, err := obj.VersionFSAt(time.Now()); !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("VersionFSAt: expected ErrVersionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedSpecVersion indicates an object declares an OCFL spec version
+// that is newer than the version implemented by this package.
+var ErrUnsupportedSpecVersion = errors.New(`unsupported OCFL spec version`)
+
+// SpecVersionErr is returned when an object's declaration or inventory type
+// refers to an unsupported OCFL spec version. It wraps
+// ErrUnsupportedSpecVersion.
+type SpecVersionErr struct {
+	Version string // the version found
+}
+
+func (e *SpecVersionErr) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnsupportedSpecVersion.Error(), e.Version)
+}
+
+func (e *SpecVersionErr) Unwrap() error {
+	return ErrUnsupportedSpecVersion
+}
+
+var inventoryTypeRegexp = regexp.MustCompile(`^https://ocfl\.io/(\d+\.\d+)/spec/#inventory$`)
+
+// SpecVersion returns the OCFL spec version declared in the inventory's type
+// field.
+func (inv *Inventory) SpecVersion() (string, error) {
+	match := inventoryTypeRegexp.FindStringSubmatch(inv.Type)
+	if match == nil {
+		return "", fmt.Errorf(`inventory type is not valid: %s`, inv.Type)
+	}
+	return match[1], nil
+}
+
+// specNewer returns true if spec version v is newer than the version
+// implemented by this package. Invalid versions are never newer.
+func specNewer(v string) bool {
+	major, minor, err := specParse(v)
+	if err != nil {
+		return false
+	}
+	impMajor, impMinor, _ := specParse(ocflVersion)
+	if major != impMajor {
+		return major > impMajor
+	}
+	return minor > impMinor
+}
+
+// specParse returns the major and minor numbers of spec version v (e.g.,
+// "1.0").
+func specParse(v string) (int, int, error) {
+	parts := strings.SplitN(v, ".", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf(`invalid spec version: %s`, v)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf(`invalid spec version: %s`, v)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf(`invalid spec version: %s`, v)
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sort"
+)
+
+// UnreferencedContent returns the paths of files in the content directories
+// of the object's versions that aren't referenced by the manifest of the root
+// inventory or any version inventory. Paths are sorted and relative to the
+// object root.
+func (obj *ObjectReader) UnreferencedContent(ctx context.Context) ([]string, error) {
+	referenced := map[string]bool{}
+	addManifest := func(inv *Inventory) error {
+		paths, err := inv.Manifest.Paths()
+		if err != nil {
+			return err
+		}
+		for p := range paths {
+			referenced[p] = true
+		}
+		return nil
+	}
+	if err := addManifest(obj.inventory); err != nil {
+		return nil, err
+	}
+	for v := range obj.inventory.Versions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		inv, err := obj.root.readInventory(v, false)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if err := addManifest(inv); err != nil {
+			return nil, err
+		}
+	}
+	var unreferenced []string
+	for v := range obj.inventory.Versions {
+		contentDir := obj.inventory.contentRef(v, "").Dir()
+		err := fs.WalkDir(obj.root, contentDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if d.Type().IsRegular() && !referenced[p] {
+				unreferenced = append(unreferenced, p)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	sort.Strings(unreferenced)
+	return unreferenced, nil
+}
@@ -0,0 +1,37 @@
+package internal_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestUnreferencedContent(t *testing.T) {
+	ctx := context.Background()
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := obj.UnreferencedContent(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected no unreferenced content, got %v", files)
+	}
+	fsys[`v2/content/leftover.txt`] = &fstest.MapFile{Data: []byte(`leftover`)}
+	fsys[`v3/content/a/b.txt`] = &fstest.MapFile{Data: []byte(`leftover`)}
+	files, err = obj.UnreferencedContent(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{`v2/content/leftover.txt`, `v3/content/a/b.txt`}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+}
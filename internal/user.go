@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Kinds of user addresses returned by User.AddressKind
+const (
+	AddressNone  = ``      // no address
+	AddressEmail = `email` // mailto: URI
+	AddressORCID = `orcid` // ORCID iD URL
+	AddressURL   = `url`   // other http or https URL
+	AddressOther = `other` // any other address
+)
+
+var orcidRegexp = regexp.MustCompile(`^https?://orcid\.org/\d{4}-\d{4}-\d{4}-\d{3}[\dX]$`)
+
+// NewUser returns a User with a mailto: address for the email address.
+func NewUser(name string, email string) (User, error) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return User{}, fmt.Errorf("invalid email address: %w", err)
+	}
+	if addr.Name != "" || addr.Address != email {
+		return User{}, fmt.Errorf("invalid email address: %s", email)
+	}
+	user := User{Name: name, Address: `mailto:` + addr.Address}
+	return user, user.Validate()
+}
+
+// NewUserURI returns a User with an address URI. The URI must be absolute,
+// with a mailto, http, or https scheme.
+func NewUserURI(name string, uri string) (User, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return User{}, err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case `mailto`, `http`, `https`:
+	default:
+		return User{}, fmt.Errorf("unsupported user address scheme: %s", uri)
+	}
+	user := User{Name: name, Address: uri}
+	return user, user.Validate()
+}
+
+// Validate returns an error if the user doesn't have a name or if its
+// address is set but isn't an absolute URI.
+func (u User) Validate() error {
+	if u.Name == "" {
+		return errors.New("user name is required")
+	}
+	if u.Address == "" {
+		return nil
+	}
+	addr, err := url.Parse(u.Address)
+	if err != nil {
+		return fmt.Errorf("user address is not a URI: %w", err)
+	}
+	if !addr.IsAbs() {
+		return fmt.Errorf("user address is not an absolute URI: %s", u.Address)
+	}
+	return nil
+}
+
+// AddressKind returns the kind of the user's address: AddressEmail,
+// AddressORCID, AddressURL, AddressOther, or AddressNone. The address is not
+// modified.
+func (u User) AddressKind() string {
+	if u.Address == "" {
+		return AddressNone
+	}
+	if orcidRegexp.MatchString(u.Address) {
+		return AddressORCID
+	}
+	addr, err := url.Parse(u.Address)
+	if err != nil {
+		return AddressOther
+	}
+	switch strings.ToLower(addr.Scheme) {
+	case `mailto`:
+		return AddressEmail
+	case `http`, `https`:
+		return AddressURL
+	}
+	return AddressOther
+}
+
+// Email returns the email address from a mailto: address, or an empty
+// string.
+func (u User) Email() string {
+	if u.AddressKind() != AddressEmail {
+		return ""
+	}
+	return u.Address[len(`mailto:`):]
+}
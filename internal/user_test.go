@@ -0,0 +1,97 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestNewUser(t *testing.T) {
+	table := map[string]struct {
+		email string
+		valid bool
+	}{
+		`ascii`:   {email: `alice@example.org`, valid: true},
+		`intl`:    {email: `用户@例子.广告`, valid: true},
+		`accents`: {email: `josé@exämple.org`, valid: true},
+		`no at`:   {email: `alice.example.org`},
+		`named`:   {email: `Alice <alice@example.org>`},
+		`empty`:   {email: ``},
+	}
+	for name, test := range table {
+		t.Run(name, func(t *testing.T) {
+			user, err := internal.NewUser(`Alice`, test.email)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected error for %q", test.email)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Address != `mailto:`+test.email {
+				t.Errorf("unexpected address: %s", user.Address)
+			}
+			if user.AddressKind() != internal.AddressEmail || user.Email() != test.email {
+				t.Errorf("unexpected kind or email: %s, %s", user.AddressKind(), user.Email())
+			}
+		})
+	}
+	if _, err := internal.NewUser(``, `alice@example.org`); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
+
+func TestUserAddressKind(t *testing.T) {
+	table := map[string]string{
+		``:                                      internal.AddressNone,
+		`mailto:alice@example.org`:              internal.AddressEmail,
+		`https://orcid.org/0000-0002-1825-0097`: internal.AddressORCID,
+		`http://orcid.org/0000-0002-1694-233X`:  internal.AddressORCID,
+		`https://example.org/~alice`:            internal.AddressURL,
+		`urn:uuid:6e8bc430-9c3a-11d9-9669`:      internal.AddressOther,
+	}
+	for addr, kind := range table {
+		u := internal.User{Name: `Alice`, Address: addr}
+		if got := u.AddressKind(); got != kind {
+			t.Errorf("AddressKind(%q): expected %q, got %q", addr, kind, got)
+		}
+	}
+}
+
+func TestNewUserURI(t *testing.T) {
+	user, err := internal.NewUserURI(`Alice`, `https://orcid.org/0000-0002-1825-0097`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.AddressKind() != internal.AddressORCID {
+		t.Errorf("expected orcid, got %s", user.AddressKind())
+	}
+	if _, err := internal.NewUserURI(`Alice`, `ftp://example.org`); err == nil {
+		t.Error("expected error for ftp scheme")
+	}
+	if _, err := internal.NewUserURI(`Alice`, `example.org`); err == nil {
+		t.Error("expected error for relative URI")
+	}
+}
+
+func TestUserRoundTrip(t *testing.T) {
+	// addresses that aren't valid URIs are preserved as stored
+	in := `{"name":"Alice","address":"alice at example dot org"}`
+	var u internal.User
+	if err := json.Unmarshal([]byte(in), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Validate() == nil {
+		t.Error("expected validation error")
+	}
+	out, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in {
+		t.Errorf("expected %s, got %s", in, out)
+	}
+}
@@ -29,15 +29,26 @@ type OCFLCodeErr struct {
 	URI         string // reference URI from spec
 }
 
-// Error implements the Error interface for ObjectValidationErr
-// func (err *OCFLCodeErr) Error() string {
-// 	return fmt.Sprintf(`[%s] %s`, err.Code, err.Description)
-// }
+// Error implements the error interface for OCFLCodeErr so codes can be used
+// as targets with errors.Is.
+func (err *OCFLCodeErr) Error() string {
+	return fmt.Sprintf(`[%s] %s`, err.Code, err.Description)
+}
 
 func (verr *validationErr) Unwrap() error {
 	return verr.err
 }
 
+// Is returns true if target is an *OCFLCodeErr with the same code as verr:
+// errors.Is(err, &ErrE093)
+func (verr *validationErr) Is(target error) bool {
+	code, ok := target.(*OCFLCodeErr)
+	if !ok || verr.code == nil {
+		return false
+	}
+	return code.Code == verr.code.Code
+}
+
 func (verr *validationErr) Error() string {
 	code := "??"
 	const format = "[%s] %s"
@@ -65,7 +76,7 @@ func (verr *validationErr) URI() string {
 	if verr.code == nil {
 		return ""
 	}
-	return verr.code.Description
+	return verr.code.URI
 }
 
 // checks if the err is a *ValidationErr. If it isn't
@@ -0,0 +1,92 @@
+package internal_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// denyFS returns fs.ErrPermission when opening name
+type denyFS struct {
+	fs.FS
+	name string
+}
+
+func (fsys *denyFS) Open(name string) (fs.File, error) {
+	if name == fsys.name {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return fsys.FS.Open(name)
+}
+
+func TestValidationErrIs(t *testing.T) {
+	for _, dir := range []string{`E003_no_decl`, `E063_no_inv`, `E058_no_sidecar`, `E092_content_file_digest_mismatch`, `E093_fixity_digest_mismatch`} {
+		t.Run(dir, func(t *testing.T) {
+			code := dir[:4]
+			result := internal.ValidateObject(loadFixture(t, filepath.Join(badObjPath, dir)))
+			var found bool
+			for _, err := range result.Fatal() {
+				if errors.Is(err, &internal.OCFLCodeErr{Code: code}) {
+					found = true
+				}
+				var vErr internal.ValidationErr
+				if !errors.As(err, &vErr) {
+					t.Errorf("expected ValidationErr, got %v", err)
+				}
+			}
+			if !found {
+				t.Errorf("expected errors.Is match for %s in %v", code, result.Fatal())
+			}
+		})
+	}
+}
+
+func TestValidationErrURI(t *testing.T) {
+	err := internal.NewValidationErr(errors.New("test"), &internal.ErrE001)
+	if err.URI() != internal.ErrE001.URI {
+		t.Errorf("unexpected URI: %s", err.URI())
+	}
+}
+
+// Files are read once to check the manifest and fixity, so read errors are
+// reported as E092, even for files with fixity values.
+func TestValidationErrPermission(t *testing.T) {
+	table := map[string]struct {
+		object string
+		file   string
+		code   *internal.OCFLCodeErr
+	}{
+		`content`: {
+			object: `minimal_one_version_one_file`,
+			file:   `v1/content/a_file.txt`,
+			code:   &internal.ErrE092,
+		},
+		`fixity`: {
+			object: `spec-ex-full`,
+			file:   `v1/content/foo/bar.xml`,
+			code:   &internal.ErrE092,
+		},
+	}
+	for name, test := range table {
+		t.Run(name, func(t *testing.T) {
+			fsys := &denyFS{
+				FS:   loadFixture(t, filepath.Join(goodObjPath, test.object)),
+				name: test.file,
+			}
+			result := internal.ValidateObject(fsys)
+			if result.Valid() {
+				t.Fatal("expected validation to fail")
+			}
+			err := result.Fatal()[0]
+			if !errors.Is(err, test.code) {
+				t.Errorf("expected %s, got %v", test.code.Code, err)
+			}
+			if !errors.Is(err, fs.ErrPermission) {
+				t.Errorf("expected fs.ErrPermission, got %v", err)
+			}
+		})
+	}
+}
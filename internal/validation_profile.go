@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// Check is a custom validation check that runs alongside the built-in
+// checks. Errors returned by Check are fatal unless demoted by the Profile.
+type Check interface {
+	Check(inv *Inventory, fsys fs.FS) []ValidationErr
+}
+
+// CheckFunc is an adapter to allow the use of ordinary functions as Checks
+type CheckFunc func(inv *Inventory, fsys fs.FS) []ValidationErr
+
+// Check implements Check for CheckFunc
+func (f CheckFunc) Check(inv *Inventory, fsys fs.FS) []ValidationErr {
+	return f(inv, fsys)
+}
+
+// Profile configures the strictness of validation.
+type Profile struct {
+	Name string
+	// PromoteWarnings treats all warnings as errors.
+	PromoteWarnings bool
+	// Promote lists warning codes (e.g., W004) that are treated as errors.
+	Promote []string
+	// Demote lists error codes that are treated as warnings. Demoting
+	// errors means invalid objects may pass validation, so Demote is
+	// ignored unless UnsafeDemote is true. Some errors stop validation, so
+	// the checks that follow them aren't performed: these errors are never
+	// demoted, so an object isn't reported as valid without being fully
+	// checked.
+	Demote       []string
+	UnsafeDemote bool
+	// Checks are custom validation checks
+	Checks []Check
+}
+
+// DefaultProfile is the Profile used if none is set
+var DefaultProfile = &Profile{Name: `default`}
+
+// StrictProfile treats all warnings as errors
+var StrictProfile = &Profile{Name: `strict`, PromoteWarnings: true}
+
+// WithProfile sets the validation profile used by Validate
+func WithProfile(p *Profile) ObjectOption {
+	return func(opts *objectOptions) {
+		opts.profile = p
+	}
+}
+
+func (opts *objectOptions) getProfile() *Profile {
+	if opts.profile == nil {
+		return DefaultProfile
+	}
+	return opts.profile
+}
+
+// NewValidationErr returns a ValidationErr for err with the given code. It
+// can be used to create errors for custom Checks.
+func NewValidationErr(err error, code *OCFLCodeErr) ValidationErr {
+	return &validationErr{err: err, code: code}
+}
+
+// apply moves errors and warnings in result according to the profile.
+func (p *Profile) apply(result *validationResult) {
+	result.profile = p.Name
+	var fatal, warnings []ValidationErr
+	for _, err := range result.fatal {
+		if p.UnsafeDemote && codeIn(err.Code(), p.Demote) && !result.stoppedBy(err) {
+			warnings = append(warnings, err)
+			continue
+		}
+		fatal = append(fatal, err)
+	}
+	for _, err := range result.warnings {
+		if p.PromoteWarnings || codeIn(err.Code(), p.Promote) {
+			fatal = append(fatal, err)
+			continue
+		}
+		warnings = append(warnings, err)
+	}
+	result.fatal, result.warnings = fatal, warnings
+}
+
+func codeIn(code string, codes []string) bool {
+	for _, c := range codes {
+		if strings.EqualFold(c, code) {
+			return true
+		}
+	}
+	return false
+}
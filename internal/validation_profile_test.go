@@ -0,0 +1,89 @@
+package internal_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestValidationProfiles(t *testing.T) {
+	fsys := os.DirFS(filepath.Join(warnObjPath, `W010_no_version_inventory`))
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatalf("expected fixture to be valid with default profile: %v", result.Fatal())
+	}
+	if result.Profile() != internal.DefaultProfile.Name {
+		t.Errorf("expected profile %s, got %s", internal.DefaultProfile.Name, result.Profile())
+	}
+	if len(result.Warning()) == 0 || result.Warning()[0].Code() != `W010` {
+		t.Errorf("expected W010 warning, got %v", result.Warning())
+	}
+	result = internal.ValidateObject(fsys, internal.WithProfile(internal.StrictProfile))
+	if result.Valid() {
+		t.Fatal("expected fixture to be invalid with strict profile")
+	}
+	if result.Profile() != internal.StrictProfile.Name {
+		t.Errorf("expected profile %s, got %s", internal.StrictProfile.Name, result.Profile())
+	}
+	if result.Fatal()[0].Code() != `W010` {
+		t.Errorf("expected W010 error, got %v", result.Fatal())
+	}
+
+	// promote a specific warning
+	sha256Obj := os.DirFS(filepath.Join(warnObjPath, `W004_uses_sha256`))
+	promote := &internal.Profile{Name: `promote`, Promote: []string{`W004`}}
+	if internal.ValidateObject(sha256Obj).Valid() == false {
+		t.Error("expected W004 fixture to be valid with default profile")
+	}
+	if internal.ValidateObject(sha256Obj, internal.WithProfile(promote)).Valid() {
+		t.Error("expected W004 fixture to be invalid with W004 promoted")
+	}
+
+	// demote an error
+	badObj := os.DirFS(filepath.Join(badObjPath, `E092_content_file_digest_mismatch`))
+	demote := &internal.Profile{Name: `demote`, Demote: []string{`E092`}}
+	if internal.ValidateObject(badObj, internal.WithProfile(demote)).Valid() {
+		t.Error("expected E092 not to be demoted without UnsafeDemote")
+	}
+	// E092 stops validation before fixity is checked, so it isn't demoted
+	demote.UnsafeDemote = true
+	result = internal.ValidateObject(badObj, internal.WithProfile(demote))
+	if result.Valid() {
+		t.Error("expected E092 not to be demoted when it stops validation")
+	}
+	// E019 doesn't stop validation
+	badObj = os.DirFS(filepath.Join(badObjPath, `E019_content_dir_changed`))
+	demote = &internal.Profile{Name: `demote`, Demote: []string{`E019`}, UnsafeDemote: true}
+	result = internal.ValidateObject(badObj, internal.WithProfile(demote))
+	if !result.Valid() {
+		t.Errorf("expected E019 to be demoted: %v", result.Fatal())
+	}
+	if len(result.Warning()) == 0 || result.Warning()[0].Code() != `E019` {
+		t.Errorf("expected E019 warning, got %v", result.Warning())
+	}
+
+	// custom check
+	maxFiles := internal.CheckFunc(func(inv *internal.Inventory, _ fs.FS) []internal.ValidationErr {
+		var errs []internal.ValidationErr
+		for vname, v := range inv.Versions {
+			paths, _ := v.State.Paths()
+			if len(paths) > 2 {
+				err := fmt.Errorf("version %s has %d files", vname, len(paths))
+				errs = append(errs, internal.NewValidationErr(err, &internal.OCFLCodeErr{Code: `LOCAL1`}))
+			}
+		}
+		return errs
+	})
+	custom := &internal.Profile{Name: `custom`, Checks: []internal.Check{maxFiles}}
+	result = internal.ValidateObject(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)), internal.WithProfile(custom))
+	if result.Valid() {
+		t.Fatal("expected custom check to fail")
+	}
+	if len(result.Fatal()) != 3 || result.Fatal()[0].Code() != `LOCAL1` {
+		t.Errorf("expected three LOCAL1 errors, got %v", result.Fatal())
+	}
+}
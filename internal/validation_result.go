@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -13,23 +14,29 @@ type ValidationResult interface {
 	Fatal() []ValidationErr
 	Warning() []ValidationErr
 	Valid() bool
+	Profile() string
+	Conformance() ConformanceInfo
+	DigestAlgorithms() map[string]string
 }
 
 // validationResult is an error returned from validation check
 type validationResult struct {
 	fatal    []ValidationErr
 	warnings []ValidationErr
+	profile  string // name of validation profile
+	// conformance of the validator that produced the result
+	conformance *ConformanceInfo
+	// digest algorithms of validated inventories, by directory
+	algorithms map[string]string
+	// fatal errors that stopped validation: checks that follow them weren't
+	// performed.
+	stopped []ValidationErr
 }
 
-// ValidateObject validates the object at root
-func ValidateObject(root fs.FS) ValidationResult {
-	vr := &validationResult{}
-	obj, err := NewObjectReader(root)
-	if err != nil {
-		return vr.AddFatal(err, nil)
-	}
-	vr.Merge(obj.Validate())
-	return vr
+// ValidateObject validates the object at root. Options are passed to
+// NewObjectReader.
+func ValidateObject(root fs.FS, opts ...ObjectOption) ValidationResult {
+	return ValidateObjectContext(context.Background(), root, opts...)
 }
 
 func (r *validationResult) Error() string {
@@ -44,6 +51,47 @@ func (r *validationResult) Warning() []ValidationErr {
 	return r.warnings
 }
 
+// Profile returns the name of the validation profile used to produce the
+// result.
+func (r *validationResult) Profile() string {
+	return r.profile
+}
+
+// Conformance returns the Conformance of the validator that produced the
+// result.
+func (r *validationResult) Conformance() ConformanceInfo {
+	if r.conformance == nil {
+		return ConformanceInfo{}
+	}
+	return *r.conformance
+}
+
+// setConformance records the current Conformance in the result.
+func (r *validationResult) setConformance() {
+	c := Conformance()
+	r.conformance = &c
+}
+
+// DigestAlgorithms returns the digest algorithms used by the inventories
+// that were validated, keyed by directory: "." for the root inventory and
+// the version directory name for version inventories. Version inventories
+// may use a different algorithm than the root inventory.
+func (r *validationResult) DigestAlgorithms() map[string]string {
+	algs := make(map[string]string, len(r.algorithms))
+	for dir, alg := range r.algorithms {
+		algs[dir] = alg
+	}
+	return algs
+}
+
+// setAlgorithm records the digest algorithm of the inventory in dir.
+func (r *validationResult) setAlgorithm(dir string, alg string) {
+	if r.algorithms == nil {
+		r.algorithms = map[string]string{}
+	}
+	r.algorithms[dir] = alg
+}
+
 func (r *validationResult) Valid() bool {
 	return len(r.fatal) == 0
 }
@@ -57,6 +105,17 @@ func (r *validationResult) Merge(err error) bool {
 		}
 		r.fatal = append(r.fatal, r2.fatal...)
 		r.warnings = append(r.warnings, r2.warnings...)
+		if r.profile == "" {
+			r.profile = r2.profile
+		}
+		if r.conformance == nil {
+			r.conformance = r2.conformance
+		}
+		for dir, alg := range r2.algorithms {
+			if _, ok := r.algorithms[dir]; !ok {
+				r.setAlgorithm(dir, alg)
+			}
+		}
 		return true
 	}
 	return false
@@ -70,6 +129,25 @@ func (r *validationResult) AddFatal(err error, code *OCFLCodeErr) *validationRes
 	return r
 }
 
+// stop adds err as a fatal error that stopped validation. Checks that
+// follow it aren't performed, so it can't be demoted by a Profile.
+func (r *validationResult) stop(err error) *validationResult {
+	n := len(r.fatal)
+	r.AddFatal(err, nil)
+	r.stopped = append(r.stopped, r.fatal[n:]...)
+	return r
+}
+
+// stoppedBy returns true if err stopped validation
+func (r *validationResult) stoppedBy(err ValidationErr) bool {
+	for _, s := range r.stopped {
+		if s == err {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *validationResult) AddWarn(err error, code *OCFLCodeErr) *validationResult {
 	if !r.Merge(err) {
 		r.warnings = append(r.warnings, asValidationErr(err, code))
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// validateVersionHistory compares the version blocks in inv, the inventory
+// in version directory dir, with the corresponding blocks in the root
+// inventory. Since the root inventory is compared to every prior
+// inventory, differences between prior inventories are found too. Changes
+// to a version's state are errors (E066); changes to its metadata are
+// warnings (W011).
+func (obj *ObjectReader) validateVersionHistory(dir string, inv *Inventory, result *validationResult) {
+	invPath := path.Join(dir, inventoryFile)
+	names := inv.VersionDirs()
+	sortVersions(names)
+	for _, vname := range names {
+		fields, verified, err := versionDiff(inv, obj.inventory, vname)
+		if err != nil {
+			result.AddFatal(err, nil)
+			continue
+		}
+		var metadata []string
+		for _, f := range fields {
+			if f == `state` || f == `missing` {
+				err := fmt.Errorf("version %s in %s differs from the root inventory: %s",
+					vname, invPath, f)
+				result.AddFatal(err, &ErrE066)
+				continue
+			}
+			metadata = append(metadata, f)
+		}
+		if len(metadata) > 0 {
+			err := fmt.Errorf("version %s in %s has different metadata than the root inventory: %s",
+				vname, invPath, strings.Join(metadata, ", "))
+			result.AddWarn(err, &ErrW011)
+		}
+		if !verified {
+			err := fmt.Errorf("cannot verify state of version %s in %s: digest algorithm changed from %s to %s",
+				vname, invPath, inv.DigestAlgorithm, obj.inventory.DigestAlgorithm)
+			result.AddWarn(err, nil)
+		}
+	}
+}
+
+// versionDiff returns the names of fields in version vname that differ
+// between the prior inventory and the current inventory. If the inventories
+// use different digest algorithms, states are compared through content
+// paths: a logical path's content in the prior state is looked up by its
+// content path in the current manifest. verified is false if any logical
+// path in the prior state couldn't be compared because none of its content
+// paths are in the current manifest.
+func versionDiff(prior *Inventory, current *Inventory, vname string) (fields []string, verified bool, err error) {
+	pv, cv := prior.Versions[vname], current.Versions[vname]
+	if cv == nil {
+		return []string{`missing`}, true, nil
+	}
+	if !pv.Created.Equal(cv.Created) {
+		fields = append(fields, `created`)
+	}
+	if pv.Message != cv.Message {
+		fields = append(fields, `message`)
+	}
+	if pv.User != cv.User {
+		fields = append(fields, `user`)
+	}
+	pPaths, err := pv.State.Paths()
+	if err != nil {
+		return nil, false, err
+	}
+	cPaths, err := cv.State.Paths()
+	if err != nil {
+		return nil, false, err
+	}
+	sameAlg := strings.EqualFold(prior.DigestAlgorithm, current.DigestAlgorithm)
+	var content map[string]string // current manifest digests by content path
+	if !sameAlg {
+		if content, err = current.Manifest.Paths(); err != nil {
+			return nil, false, err
+		}
+	}
+	verified = true
+	stateDiff := len(pPaths) != len(cPaths)
+	for p, pDigest := range pPaths {
+		if stateDiff {
+			break
+		}
+		cDigest, ok := cPaths[p]
+		switch {
+		case !ok:
+			stateDiff = true
+		case sameAlg:
+			stateDiff = !strings.EqualFold(pDigest, cDigest)
+		default:
+			digest, found := currentDigest(prior.Manifest[pDigest], content)
+			if !found {
+				verified = false
+				continue
+			}
+			stateDiff = !strings.EqualFold(digest, cDigest)
+		}
+	}
+	if stateDiff {
+		fields = append(fields, `state`)
+	}
+	return fields, verified, nil
+}
+
+// currentDigest returns the digest in content, a map of content paths to
+// digests, for the first of contentPaths found in it.
+func currentDigest(contentPaths []string, content map[string]string) (string, bool) {
+	for _, p := range contentPaths {
+		if digest, ok := content[p]; ok {
+			return digest, true
+		}
+	}
+	return "", false
+}
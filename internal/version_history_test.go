@@ -0,0 +1,126 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// setInventory replaces the inventory and sidecar in dir with inv
+func setInventory(t testing.TB, fsys fstest.MapFS, dir string, inv map[string]interface{}) {
+	t.Helper()
+	data, err := json.Marshal(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.ToSlash(filepath.Join(dir, `inventory.json`))
+	fsys[name] = &fstest.MapFile{Data: data}
+	fsys[name+`.sha512`] = &fstest.MapFile{Data: []byte(sha512Hex(data) + " inventory.json\n")}
+}
+
+func TestVersionHistory(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	// edit v1 in the root inventory (and the matching head inventory)
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	v1 := inv["versions"].(map[string]interface{})["v1"].(map[string]interface{})
+	v1["message"] = "rewritten history"
+	// rename a logical path
+	state := v1["state"].(map[string]interface{})
+	for digest, paths := range state {
+		if paths.([]interface{})[0] == "foo/bar.xml" {
+			state[digest] = []string{"foo/renamed.xml"}
+		}
+	}
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v3`, inv)
+	result = internal.ValidateObject(fsys)
+	if result.Valid() {
+		t.Fatal("expected validation to fail")
+	}
+	var count int
+	for _, err := range result.Fatal() {
+		if errors.Is(err, &internal.ErrE066) {
+			count++
+		}
+	}
+	// v1 block differs in v1/inventory.json and v2/inventory.json
+	if count != 2 {
+		t.Errorf("expected 2 E066 errors, got %v", result.Fatal())
+	}
+	count = 0
+	for _, err := range result.Warning() {
+		if errors.Is(err, &internal.ErrW011) {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected 2 W011 warnings, got %v", result.Warning())
+	}
+}
+
+func TestVersionHistoryAlgorithmChange(t *testing.T) {
+	// v1 and v2 inventories use sha256; v3 and the root use sha512
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `updates_digest_algorithm_change`))
+	result := internal.ValidateObject(fsys)
+	if !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	if len(result.Warning()) > 0 {
+		t.Errorf("unexpected warnings: %v", result.Warning())
+	}
+	expected := map[string]string{
+		".":  "sha512",
+		"v1": "sha256",
+		"v2": "sha256",
+		"v3": "sha512",
+	}
+	algs := result.DigestAlgorithms()
+	if len(algs) != len(expected) {
+		t.Errorf("expected algorithms %v, got %v", expected, algs)
+	}
+	for dir, alg := range expected {
+		if algs[dir] != alg {
+			t.Errorf("expected %s for %s, got %q", alg, dir, algs[dir])
+		}
+	}
+	// swap logical paths in v1 in the root inventory (and the matching head
+	// inventory): the state differs from the sha256 inventories.
+	var inv map[string]interface{}
+	if err := json.Unmarshal(fsys[`inventory.json`].Data, &inv); err != nil {
+		t.Fatal(err)
+	}
+	v1 := inv["versions"].(map[string]interface{})["v1"].(map[string]interface{})
+	state := v1["state"].(map[string]interface{})
+	var digests []string
+	for digest := range state {
+		digests = append(digests, digest)
+	}
+	if len(digests) != 2 {
+		t.Fatalf("expected 2 digests in v1 state, got %d", len(digests))
+	}
+	state[digests[0]], state[digests[1]] = state[digests[1]], state[digests[0]]
+	setInventory(t, fsys, `.`, inv)
+	setInventory(t, fsys, `v3`, inv)
+	result = internal.ValidateObject(fsys)
+	var count int
+	for _, err := range result.Fatal() {
+		if errors.Is(err, &internal.ErrE066) {
+			count++
+		}
+	}
+	// v1 block differs in v1/inventory.json and v2/inventory.json
+	if count != 2 {
+		t.Errorf("expected 2 E066 errors, got %v", result.Fatal())
+	}
+}
@@ -20,6 +20,7 @@ const (
 )
 
 var ErrVersionInvalid = errors.New(`invalid version name format`)
+var ErrVersionNotFound = errors.New(`version not found`)
 
 var vFmtRegexps = map[versionFmt]*regexp.Regexp{
 	vPaddedFmt:   regexp.MustCompile(`^v0\d+$`),
@@ -147,3 +148,16 @@ func versionSeqValid(names []string) error {
 	}
 	return nil
 }
+
+// sortVersions sorts version names by version number. Invalid names are
+// sorted last.
+func sortVersions(names []string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		vi, _, erri := versionParse(names[i])
+		vj, _, errj := versionParse(names[j])
+		if erri != nil || errj != nil {
+			return erri == nil
+		}
+		return vi < vj
+	})
+}
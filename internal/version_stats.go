@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// VersionStatsPath is the location of the version stats extension file in
+// the object root.
+const VersionStatsPath = extensionsDir + `/version-stats/stats.json`
+
+// ErrVersionStatsStale indicates the version stats extension file was
+// written for a different object head.
+var ErrVersionStatsStale = errors.New(`version stats are stale`)
+
+// VersionStats summarizes the logical state of a version. Stats are for
+// display only: they are not verified and must not be used to check an
+// object's integrity.
+type VersionStats struct {
+	Files int   `json:"files"` // number of logical paths
+	Bytes int64 `json:"bytes"` // total size of logical files
+}
+
+// VersionStatsFile is the contents of the version stats extension file.
+type VersionStatsFile struct {
+	Head     string                  `json:"head"`
+	Versions map[string]VersionStats `json:"versions"`
+}
+
+// VersionStats returns stats for version vname from the version stats
+// extension file (see VersionStatsPath). It returns an error wrapping
+// fs.ErrNotExist if the file doesn't exist, or ErrVersionStatsStale if it
+// wasn't written for the object's current head. In either case, stats can
+// be calculated with RecomputeVersionStats.
+func (obj *ObjectReader) VersionStats(vname string) (VersionStats, error) {
+	if _, ok := obj.inventory.Versions[vname]; !ok {
+		return VersionStats{}, fmt.Errorf("%w: %s", ErrVersionNotFound, vname)
+	}
+	statsFile, err := obj.readVersionStats()
+	if err != nil {
+		return VersionStats{}, err
+	}
+	stats, ok := statsFile.Versions[vname]
+	if !ok {
+		return VersionStats{}, fmt.Errorf("%w: no entry for %s", ErrVersionStatsStale, vname)
+	}
+	return stats, nil
+}
+
+// readVersionStats reads the version stats extension file. It returns an
+// error wrapping ErrVersionStatsStale if the file wasn't written for the
+// object's current head.
+func (obj *ObjectReader) readVersionStats() (VersionStatsFile, error) {
+	var statsFile VersionStatsFile
+	data, err := fs.ReadFile(obj.root, VersionStatsPath)
+	if err != nil {
+		return statsFile, err
+	}
+	if err := json.Unmarshal(data, &statsFile); err != nil {
+		return statsFile, fmt.Errorf("reading %s: %w", VersionStatsPath, err)
+	}
+	if statsFile.Head != obj.inventory.Head {
+		return statsFile, fmt.Errorf("%w: written for %s, head is %s", ErrVersionStatsStale, statsFile.Head, obj.inventory.Head)
+	}
+	return statsFile, nil
+}
+
+// RecomputeVersionStats calculates stats for every version by stating
+// content files. Content shared by several logical paths or versions is
+// only stated once. The result can be serialized as the version stats
+// extension file.
+func (obj *ObjectReader) RecomputeVersionStats(ctx context.Context) (VersionStatsFile, error) {
+	statsFile := VersionStatsFile{
+		Head:     obj.inventory.Head,
+		Versions: make(map[string]VersionStats, len(obj.inventory.Versions)),
+	}
+	sizes := map[string]int64{}
+	for vname, ver := range obj.inventory.Versions {
+		var stats VersionStats
+		for digest, paths := range ver.State {
+			size, ok := sizes[digest]
+			if !ok {
+				if err := ctx.Err(); err != nil {
+					return statsFile, err
+				}
+				contentPaths := obj.inventory.Manifest[digest]
+				if len(contentPaths) == 0 {
+					return statsFile, fmt.Errorf("digest not in manifest: %s", digest)
+				}
+				info, err := fs.Stat(obj.root, path.Clean(contentPaths[0]))
+				if err != nil {
+					return statsFile, err
+				}
+				size = info.Size()
+				sizes[digest] = size
+			}
+			stats.Files += len(paths)
+			stats.Bytes += size * int64(len(paths))
+		}
+		statsFile.Versions[vname] = stats
+	}
+	return statsFile, nil
+}
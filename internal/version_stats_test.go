@@ -0,0 +1,67 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+func TestVersionStats(t *testing.T) {
+	fsys := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+	obj, err := internal.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.VersionStats(`v1`); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got %v", err)
+	}
+	statsFile, err := obj.RecomputeVersionStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]internal.VersionStats{
+		`v1`: {Files: 3, Bytes: 2293},
+		`v2`: {Files: 3, Bytes: 272},
+		`v3`: {Files: 3, Bytes: 2293},
+	}
+	for v, stats := range expected {
+		if statsFile.Versions[v] != stats {
+			t.Errorf("%s: expected %v, got %v", v, stats, statsFile.Versions[v])
+		}
+	}
+	data, err := json.Marshal(statsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys[internal.VersionStatsPath] = &fstest.MapFile{Data: data}
+	stats, err := obj.VersionStats(`v1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != expected[`v1`] {
+		t.Errorf("expected %v, got %v", expected[`v1`], stats)
+	}
+	if _, err := obj.VersionStats(`v4`); !errors.Is(err, internal.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+	// the extension doesn't affect validation
+	if result := internal.ValidateObject(fsys); !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	// stale
+	statsFile.Head = `v2`
+	data, err = json.Marshal(statsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys[internal.VersionStatsPath] = &fstest.MapFile{Data: data}
+	if _, err := obj.VersionStats(`v1`); !errors.Is(err, internal.ErrVersionStatsStale) {
+		t.Errorf("expected ErrVersionStatsStale, got %v", err)
+	}
+}
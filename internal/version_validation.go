@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// ValidateVersionDir validates the version directory dir in fsys, the object
+// root, without the rest of the object. The version inventory and sidecar
+// are validated, manifest entries for content in dir are checked for
+// existence and fixity, and content files in dir that aren't in the manifest
+// are reported. Manifest entries for content in other versions can't be
+// verified and are reported as warnings. An error is returned if the
+// validation could not be performed, e.g., if ctx is canceled or dir
+// doesn't exist.
+func ValidateVersionDir(ctx context.Context, fsys fs.FS, dir string) (ValidationResult, error) {
+	result := &validationResult{}
+	result.setConformance()
+	defer DefaultProfile.apply(result)
+	if _, _, err := versionParse(dir); err != nil {
+		return result, err
+	}
+	if _, err := fs.Stat(fsys, dir); err != nil {
+		return result, err
+	}
+	root := &objectRoot{FS: fsys}
+	inv, err := root.readInventory(dir, true)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return result.AddFatal(err, &ErrE034), nil
+		}
+		return result.AddFatal(err, nil), nil
+	}
+	result.setAlgorithm(dir, inv.DigestAlgorithm)
+	if inv.Head != dir {
+		err := fmt.Errorf(`version inventory head is %s, expected %s`, inv.Head, dir)
+		result.AddFatal(err, &ErrE040)
+	}
+	manifest, err := inv.Manifest.Normalize()
+	if err != nil {
+		return result.AddFatal(err, nil), nil
+	}
+	paths, err := manifest.Paths()
+	if err != nil {
+		return result.AddFatal(err, nil), nil
+	}
+	contentDir := inv.contentRef(dir, "").Dir()
+	// manifest paths by content directory
+	expected := map[string]string{}
+	unverifiable := map[string]int{}
+	for p, digest := range paths {
+		ref, err := ParseContentRef(p)
+		if err != nil {
+			unverifiable[strings.SplitN(p, "/", 2)[0]]++
+			continue
+		}
+		if ref.Version == dir && ref.ContentDir == inv.contentDirectory() {
+			expected[p] = digest
+			continue
+		}
+		unverifiable[ref.Version]++
+	}
+	var extra []string
+	each := func(name string, digest string, _ int64) error {
+		expectedDigest, ok := expected[name]
+		if !ok {
+			extra = append(extra, name)
+			return nil
+		}
+		delete(expected, name)
+		if digest != expectedDigest {
+			err := fmt.Errorf(`content digest doesn't match manifest: %s`, name)
+			result.AddFatal(err, &ErrE092)
+		}
+		return nil
+	}
+	err = walkDigests(ctx, fsys, contentDir, inv.DigestAlgorithm, true, nil, each)
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	if err != nil {
+		return result.AddFatal(err, nil), nil
+	}
+	sort.Strings(extra)
+	for _, p := range extra {
+		err := fmt.Errorf(`content file not in manifest: %s`, p)
+		result.AddFatal(err, &ErrE023)
+	}
+	missing := make([]string, 0, len(expected))
+	for p := range expected {
+		missing = append(missing, p)
+	}
+	sort.Strings(missing)
+	for _, p := range missing {
+		err := fmt.Errorf(`manifest content not found: %s`, p)
+		result.AddFatal(err, &ErrE092)
+	}
+	versions := make([]string, 0, len(unverifiable))
+	for v := range unverifiable {
+		versions = append(versions, v)
+	}
+	sortVersions(versions)
+	for _, v := range versions {
+		err := fmt.Errorf(`%d content path(s) in %s can't be verified here`, unverifiable[v], v)
+		result.AddWarn(err, nil)
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl/internal"
+)
+
+// versionOnly returns a copy of fsys with only files in version dir v
+func versionOnly(fsys fstest.MapFS, v string) fstest.MapFS {
+	out := fstest.MapFS{}
+	for name, file := range fsys {
+		if strings.HasPrefix(name, v+"/") {
+			out[name] = file
+		}
+	}
+	return out
+}
+
+func TestValidateVersionDir(t *testing.T) {
+	ctx := context.Background()
+	fixture := loadFixture(t, filepath.Join(goodObjPath, `spec-ex-full`))
+
+	t.Run("valid", func(t *testing.T) {
+		result, err := internal.ValidateVersionDir(ctx, versionOnly(fixture, `v1`), `v1`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Valid() || len(result.Warning()) != 0 {
+			t.Fatal(result.Fatal(), result.Warning())
+		}
+	})
+
+	t.Run("unverifiable", func(t *testing.T) {
+		result, err := internal.ValidateVersionDir(ctx, versionOnly(fixture, `v2`), `v2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Valid() {
+			t.Fatal(result.Fatal())
+		}
+		// content from v1
+		if len(result.Warning()) != 1 {
+			t.Errorf("expected one warning, got %v", result.Warning())
+		}
+	})
+
+	t.Run("bad content", func(t *testing.T) {
+		fsys := versionOnly(fixture, `v1`)
+		fsys[`v1/content/image.tiff`] = &fstest.MapFile{Data: []byte(`changed`)}
+		fsys[`v1/content/extra.txt`] = &fstest.MapFile{Data: []byte(`extra`)}
+		delete(fsys, `v1/content/empty.txt`)
+		result, err := internal.ValidateVersionDir(ctx, fsys, `v1`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		codes := map[string]int{}
+		for _, err := range result.Fatal() {
+			codes[err.Code()]++
+		}
+		if codes[`E092`] != 2 || codes[`E023`] != 1 {
+			t.Errorf("unexpected errors: %v", result.Fatal())
+		}
+	})
+
+	t.Run("bad sidecar", func(t *testing.T) {
+		fsys := versionOnly(fixture, `v1`)
+		fsys[`v1/inventory.json.sha512`] = &fstest.MapFile{Data: []byte("abc inventory.json\n")}
+		result, err := internal.ValidateVersionDir(ctx, fsys, `v1`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Valid() {
+			t.Error("expected invalid result")
+		}
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		_, err := internal.ValidateVersionDir(ctx, versionOnly(fixture, `v1`), `v2`)
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected ErrNotExist, got %v", err)
+		}
+	})
+}
@@ -1,23 +1,784 @@
 package ocfl
 
 import (
+	"context"
+	"hash"
 	"io/fs"
+	"time"
 
 	"github.com/srerickson/ocfl/internal"
 )
 
-const Version = "0.0.0"
+const Version = internal.LibraryVersion
 
 type ObjectReader internal.ObjectReader
 type ValidationResult internal.ValidationResult
+type ObjectOption internal.ObjectOption
+
+// Metrics is an interface for instrumenting long-running operations. See
+// WithMetrics.
+type Metrics internal.Metrics
+
+// ErrUnsupportedSpecVersion is returned by NewObjectReader if the object
+// declares an OCFL spec version newer than the one implemented.
+var ErrUnsupportedSpecVersion = internal.ErrUnsupportedSpecVersion
+
+// ErrVersionNotFound is wrapped by errors for version names that aren't in
+// the object's inventory.
+var ErrVersionNotFound = internal.ErrVersionNotFound
+
+// ErrNotContentPath is wrapped by errors for paths that aren't content paths
+// in the inventory. See Inventory.VersionOfContentPath.
+var ErrNotContentPath = internal.ErrNotContentPath
+
+// Errors for declaration and inventory files that are accepted with
+// WithPermissiveParsing.
+var (
+	ErrDeclarationCRLF      = internal.ErrDeclarationCRLF
+	ErrDeclarationNoNewline = internal.ErrDeclarationNoNewline
+	ErrInventoryBOM         = internal.ErrInventoryBOM
+)
 
 func (obj *ObjectReader) LogicalFS() (fs.FS, error) {
 	return (*internal.ObjectReader)(obj).LogicalFS()
 }
 
+// SpecVersion returns the OCFL spec version declared by the object.
+func (obj *ObjectReader) SpecVersion() string {
+	return (*internal.ObjectReader)(obj).SpecVersion()
+}
+
+// IntegrityReport describes inconsistencies in an object root that may
+// result from an interrupted update.
+type IntegrityReport = internal.IntegrityReport
+
+// CheckIntegrity checks the object root for states associated with an
+// interrupted update.
+func (obj *ObjectReader) CheckIntegrity(ctx context.Context) (IntegrityReport, error) {
+	return (*internal.ObjectReader)(obj).CheckIntegrity(ctx)
+}
+
+// Inventory represents the contents of an OCFL object's inventory.json
+type Inventory = internal.Inventory
+
+// PathTree is the directory tree of a version's logical paths, returned by
+// Version.StateTree.
+type PathTree = internal.PathTree
+
+// PathConflictErr is returned if a path is duplicated or used as both a file
+// and a directory.
+type PathConflictErr = internal.PathConflictErr
+
+// InventoryRecord describes an inventory file in the object root or a version
+// directory, along with its sidecar.
+type InventoryRecord = internal.InventoryRecord
+
+// Inventories returns InventoryRecords for the root inventory and each
+// version directory's inventory.
+func (obj *ObjectReader) Inventories(ctx context.Context) ([]InventoryRecord, error) {
+	return (*internal.ObjectReader)(obj).Inventories(ctx)
+}
+
+// InventoryAt returns the InventoryRecord for version directory vname, or
+// for the root inventory if vname is empty.
+func (obj *ObjectReader) InventoryAt(vname string) (InventoryRecord, error) {
+	return (*internal.ObjectReader)(obj).InventoryAt(vname)
+}
+
+// TimeRef is the result of resolving a time to a version with
+// Inventory.ResolveTime.
+type TimeRef = internal.TimeRef
+
+// VersionTime is a version name and its created timestamp, returned by
+// Inventory.Chronology.
+type VersionTime = internal.VersionTime
+
+// ErrCreatedOrder is the code for warnings about versions created before the
+// previous version. It can be used with Profile.Promote.
+var ErrCreatedOrder = internal.ErrCreatedOrder
+
+// VersionFSAt returns an fs.FS for the logical state of the latest version
+// created at or before t.
+func (obj *ObjectReader) VersionFSAt(t time.Time) (fs.FS, error) {
+	return (*internal.ObjectReader)(obj).VersionFSAt(t)
+}
+
+// RootSnapshot records digests of an object's root files. See
+// ObjectReader.SnapshotRoot.
+type RootSnapshot = internal.RootSnapshot
+
+// RootChangeErr describes changes to an object's root files since a
+// RootSnapshot.
+type RootChangeErr = internal.RootChangeErr
+
+// ErrRootTampered indicates the object root files changed without a new
+// version.
+var ErrRootTampered = internal.ErrRootTampered
+
+// SnapshotRoot returns digests of the object's declaration, root inventory,
+// and inventory sidecar.
+func (obj *ObjectReader) SnapshotRoot(ctx context.Context) (RootSnapshot, error) {
+	return (*internal.ObjectReader)(obj).SnapshotRoot(ctx)
+}
+
+// VerifySnapshot compares the object's root files to snap. It returns a
+// *RootChangeErr if any of them changed.
+func (obj *ObjectReader) VerifySnapshot(ctx context.Context, snap RootSnapshot) error {
+	return (*internal.ObjectReader)(obj).VerifySnapshot(ctx, snap)
+}
+
+// UnreferencedContent returns paths of content files that aren't referenced
+// by the manifest of any of the object's inventories.
+func (obj *ObjectReader) UnreferencedContent(ctx context.Context) ([]string, error) {
+	return (*internal.ObjectReader)(obj).UnreferencedContent(ctx)
+}
+
+// ListOption configures ObjectReader.ListFiles
+type ListOption = internal.ListOption
+
+// FileList is a page of entries returned by ListFiles.
+type FileList = internal.FileList
+
+// FileEntry is a logical file or directory returned by ListFiles.
+type FileEntry = internal.FileEntry
+
+// ErrListTokenInvalid indicates a malformed or stale ListFiles continuation
+// token.
+var ErrListTokenInvalid = internal.ErrListTokenInvalid
+
+// WithListPrefix limits ListFiles to logical paths beginning with prefix.
+func WithListPrefix(prefix string) ListOption {
+	return internal.WithListPrefix(prefix)
+}
+
+// WithListDirs configures ListFiles to return immediate children of the
+// prefix directory.
+func WithListDirs() ListOption {
+	return internal.WithListDirs()
+}
+
+// WithListPage sets the page size and continuation token for ListFiles.
+func WithListPage(size int, token string) ListOption {
+	return internal.WithListPage(size, token)
+}
+
+// ListFiles returns a sorted page of entries in the logical state of version
+// vname.
+func (obj *ObjectReader) ListFiles(vname string, opts ...ListOption) (FileList, error) {
+	return (*internal.ObjectReader)(obj).ListFiles(vname, opts...)
+}
+
+// VersionStats summarizes the logical state of a version for display.
+type VersionStats = internal.VersionStats
+
+// VersionStatsFile is the contents of the version stats extension file.
+type VersionStatsFile = internal.VersionStatsFile
+
+// ErrVersionStatsStale indicates the version stats extension file was
+// written for a different object head.
+var ErrVersionStatsStale = internal.ErrVersionStatsStale
+
+// VersionStats returns stats for version vname from the version stats
+// extension file.
+func (obj *ObjectReader) VersionStats(vname string) (VersionStats, error) {
+	return (*internal.ObjectReader)(obj).VersionStats(vname)
+}
+
+// RecomputeVersionStats calculates stats for every version from the
+// object's content files.
+func (obj *ObjectReader) RecomputeVersionStats(ctx context.Context) (VersionStatsFile, error) {
+	return (*internal.ObjectReader)(obj).RecomputeVersionStats(ctx)
+}
+
+// ContentFileInfo is implemented by the fs.FileInfo of files in an
+// ObjectReader's LogicalFS. It identifies the content file that was read.
+type ContentFileInfo = internal.ContentFileInfo
+
+// ResolvedPath returns the content path that is read for logical, a path in
+// the ObjectReader's LogicalFS.
+func (obj *ObjectReader) ResolvedPath(logical string) (string, error) {
+	return (*internal.ObjectReader)(obj).ResolvedPath(logical)
+}
+
+// WithoutInventoryCache configures NewObjectReader not to retain the root
+// inventory's bytes. InventoryBytes and InventoryDigest read the file again.
+func WithoutInventoryCache() ObjectOption {
+	return ObjectOption(internal.WithoutInventoryCache())
+}
+
+// InventoryBytes returns the exact contents of the inventory file the
+// ObjectReader was opened with.
+func (obj *ObjectReader) InventoryBytes() ([]byte, error) {
+	return (*internal.ObjectReader)(obj).InventoryBytes()
+}
+
+// InventoryDigest returns the inventory's digest algorithm and the digest of
+// the bytes returned by InventoryBytes. An error matching
+// ErrInventoryChecksum is returned with the digest if it doesn't match the
+// sidecar.
+func (obj *ObjectReader) InventoryDigest() (alg string, digest string, err error) {
+	return (*internal.ObjectReader)(obj).InventoryDigest()
+}
+
+// OpenContextFS is implemented by backends that can cancel requests. It is
+// used to open files during operations that take a context.
+type OpenContextFS = internal.OpenContextFS
+
+// NewObjectReaderContext is like NewObjectReader, but ctx is used while
+// reading the object's declaration and inventory.
+func NewObjectReaderContext(ctx context.Context, fsys fs.FS, opts ...ObjectOption) (*ObjectReader, error) {
+	obj, err := internal.NewObjectReaderContext(ctx, fsys, internalOpts(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return (*ObjectReader)(obj), nil
+}
+
+// ValidateObjectContext is like ValidateObject, but validation stops if ctx
+// is canceled.
+func ValidateObjectContext(ctx context.Context, fsys fs.FS, opts ...ObjectOption) ValidationResult {
+	return internal.ValidateObjectContext(ctx, fsys, internalOpts(opts)...)
+}
+
+// ValidateContext fully validates the object, including content fixity.
+// Validation stops if ctx is canceled, and the context's error is included
+// in the result's fatal errors.
+func (obj *ObjectReader) ValidateContext(ctx context.Context) ValidationResult {
+	return (*internal.ObjectReader)(obj).ValidateContext(ctx)
+}
+
+// ContentContext returns a DigestMap of all version contents. Digesting
+// stops if ctx is canceled.
+func (obj *ObjectReader) ContentContext(ctx context.Context) (DigestMap, error) {
+	return (*internal.ObjectReader)(obj).ContentContext(ctx)
+}
+
+// CRC32C is a non-cryptographic checksum algorithm for FastIndex.
+const CRC32C = internal.CRC32C
+
+// FastIndex holds non-cryptographic checksums of an object's content files
+// for quick integrity sweeps.
+type FastIndex = internal.FastIndex
+
+// FastReport is the result of VerifyFast.
+type FastReport = internal.FastReport
+
+// BuildFastIndex returns a FastIndex with alg checksums for every content
+// path in the manifest.
+func (obj *ObjectReader) BuildFastIndex(ctx context.Context, alg string) (FastIndex, error) {
+	return (*internal.ObjectReader)(obj).BuildFastIndex(ctx, alg)
+}
+
+// VerifyFast checks the object's content against idx. Mismatches are
+// confirmed with the inventory's digest algorithm before they are reported
+// as corruption.
+func (obj *ObjectReader) VerifyFast(ctx context.Context, idx FastIndex) (FastReport, error) {
+	return (*internal.ObjectReader)(obj).VerifyFast(ctx, idx)
+}
+
+// ContentSinceOption is used to configure ValidateContentSince
+type ContentSinceOption = internal.ContentSinceOption
+
+// ContentCoverage describes the content checked by ValidateContentSince.
+type ContentCoverage = internal.ContentCoverage
+
+// WithBackfillSample configures ValidateContentSince to also check a
+// deterministic sample of older content.
+func WithBackfillSample(fraction float64) ContentSinceOption {
+	return internal.WithBackfillSample(fraction)
+}
+
+// ValidateContentSince checks the digests of content in version directories
+// newer than sinceVersion.
+func (obj *ObjectReader) ValidateContentSince(ctx context.Context, sinceVersion string, opts ...ContentSinceOption) (ValidationResult, ContentCoverage, error) {
+	return (*internal.ObjectReader)(obj).ValidateContentSince(ctx, sinceVersion, opts...)
+}
+
+// Event is an entry in an object's event history.
+type Event = internal.Event
+
+// EventSink receives events from operations on an object.
+type EventSink = internal.EventSink
+
+// Event types
+const (
+	EventVersionCreated = internal.EventVersionCreated
+	EventValidated      = internal.EventValidated
+)
+
+// WithEventSink sets an EventSink that receives an event when validation
+// completes.
+func WithEventSink(sink EventSink) ObjectOption {
+	return ObjectOption(internal.WithEventSink(sink))
+}
+
+// History returns a version-created event for each version in the
+// inventory, in version order.
+func (obj *ObjectReader) History() []Event {
+	return (*internal.ObjectReader)(obj).History()
+}
+
+// Probe is basic information about an object that can be read without
+// parsing its inventory.
+type Probe = internal.Probe
+
+// ProbeObject reads the object declaration and inventory sidecar of the
+// object in directory dir of fsys.
+func ProbeObject(ctx context.Context, fsys fs.FS, dir string) (*Probe, error) {
+	return internal.ProbeObject(ctx, fsys, dir)
+}
+
+// ContentOption is used to configure ScanContent
+type ContentOption = internal.ContentOption
+
+// ContentResult is returned by ScanContent.
+type ContentResult = internal.ContentResult
+
+// ContentFailure is a content file or directory that couldn't be read.
+type ContentFailure = internal.ContentFailure
+
+// WithFailFast configures ScanContent to return the first error reading a
+// content file.
+func WithFailFast() ContentOption {
+	return internal.WithFailFast()
+}
+
+// ScanContent digests every file in the object's version content
+// directories. Files and directories that can't be read are reported in the
+// result's Failures.
+func (obj *ObjectReader) ScanContent(ctx context.Context, opts ...ContentOption) (ContentResult, error) {
+	return (*internal.ObjectReader)(obj).ScanContent(ctx, opts...)
+}
+
+// Declaration is a NAMASTE declaration of an OCFL object or storage root.
+type Declaration = internal.Declaration
+
+// Declaration kinds
+const (
+	DeclObject = internal.DeclObject
+	DeclRoot   = internal.DeclRoot
+)
+
+// Errors returned by FindDeclaration and Declaration.Validate
+var (
+	ErrDeclarationNotFound = internal.ErrDeclarationNotFound
+	ErrDeclarationMultiple = internal.ErrDeclarationMultiple
+	ErrDeclarationContents = internal.ErrDeclarationContents
+)
+
+// ParseDeclaration parses a declaration file name, returning its kind and
+// OCFL spec version. ok is false if name isn't an OCFL declaration.
+func ParseDeclaration(name string) (kind string, version string, ok bool) {
+	return internal.ParseDeclaration(name)
+}
+
+// FindDeclaration returns the declaration among directory entries.
+func FindDeclaration(entries []fs.DirEntry) (Declaration, error) {
+	return internal.FindDeclaration(entries)
+}
+
+// DigestMap maps digests to paths, as in an inventory manifest or version
+// state.
+type DigestMap = internal.DigestMap
+
+// MergeConflict is a logical path changed differently by both sides of
+// MergeStates.
+type MergeConflict = internal.MergeConflict
+
+// Kinds of MergeConflict
+const (
+	ConflictAddAdd       = internal.ConflictAddAdd
+	ConflictModifyModify = internal.ConflictModifyModify
+	ConflictDeleteModify = internal.ConflictDeleteModify
+	ConflictRename       = internal.ConflictRename
+)
+
+// MergeStates does a three-way merge of version states ours and theirs,
+// which are both changes to base. Conflicting paths are returned with the
+// merged state, which has ours for those paths.
+func MergeStates(base, ours, theirs DigestMap) (DigestMap, []MergeConflict, error) {
+	return internal.MergeStates(base, ours, theirs)
+}
+
+// StringInvalidErr is a string in an inventory that isn't valid UTF-8 or
+// that contains control characters.
+type StringInvalidErr = internal.StringInvalidErr
+
+// ConformanceInfo describes the OCFL spec versions, digest algorithms, and
+// extensions supported by this library.
+type ConformanceInfo = internal.ConformanceInfo
+
+// Conformance returns a snapshot of the OCFL features currently supported,
+// including extensions registered with RegisterExtensionValidator.
+func Conformance() ConformanceInfo {
+	return internal.Conformance()
+}
+
+// SupportedSpecVersions returns the OCFL spec versions implemented by this
+// library.
+func SupportedSpecVersions() []string {
+	return internal.SupportedSpecVersions()
+}
+
+// SupportedDigestAlgorithms returns the names of supported digest
+// algorithms.
+func SupportedDigestAlgorithms() []string {
+	return internal.SupportedDigestAlgorithms()
+}
+
+// SupportedExtensions returns the names of extensions with registered
+// validators.
+func SupportedExtensions() []string {
+	return internal.SupportedExtensions()
+}
+
+// Capabilities describes optional features of a storage backend.
+type Capabilities = internal.Capabilities
+
+// CapabilitiesFS is implemented by backends that declare their
+// Capabilities.
+type CapabilitiesFS = internal.CapabilitiesFS
+
+// CapabilitiesOf returns the declared or inferred Capabilities of fsys.
+func CapabilitiesOf(fsys fs.FS) Capabilities {
+	return internal.CapabilitiesOf(fsys)
+}
+
+// ListingOption configures ValidateAgainstListing
+type ListingOption = internal.ListingOption
+
+// WithExpectedSizes sets expected sizes for content paths checked by
+// ValidateAgainstListing.
+func WithExpectedSizes(sizes map[string]int64) ListingOption {
+	return internal.WithExpectedSizes(sizes)
+}
+
+// WithListingStats sets the object's version stats, which
+// ValidateAgainstListing checks against listed content sizes.
+func WithListingStats(stats VersionStatsFile) ListingOption {
+	return internal.WithListingStats(stats)
+}
+
+// ValidateAgainstListing validates the object with inventory inv using a
+// listing of its files and their sizes, without reading them. Keys in
+// listing are slash-separated paths relative to the object root.
+func ValidateAgainstListing(inv *Inventory, listing map[string]int64, opts ...ListingOption) (ValidationResult, error) {
+	return internal.ValidateAgainstListing(inv, listing, opts...)
+}
+
+// Listing returns the regular files in fsys and their sizes, for use with
+// ValidateAgainstListing.
+func Listing(fsys fs.FS) (map[string]int64, error) {
+	return internal.Listing(fsys)
+}
+
+// AmbiguousDeclarationErr is returned if an object root has more than one
+// NAMASTE declaration. See WithSpecVersion.
+type AmbiguousDeclarationErr = internal.AmbiguousDeclarationErr
+
+// WithSpecVersion sets the OCFL spec version used to choose the object
+// declaration if the object root has more than one.
+func WithSpecVersion(v string) ObjectOption {
+	return ObjectOption(internal.WithSpecVersion(v))
+}
+
+// ErrUnsupportedAlgorithm indicates a digest algorithm that isn't built in
+// or registered with RegisterDigestAlgorithm.
+var ErrUnsupportedAlgorithm = internal.ErrUnsupportedAlgorithm
+
+// RegisterDigestAlgorithm registers newH as the implementation of digest
+// algorithm name, so fixity values using it can be verified.
+func RegisterDigestAlgorithm(name string, newH func() hash.Hash) {
+	internal.RegisterDigestAlgorithm(name, newH)
+}
+
+// WithAlg sets the digest algorithm used by ScanContent and ValidateContent.
+func WithAlg(alg string) ContentOption {
+	return internal.WithAlg(alg)
+}
+
+// ValidateContent checks the digests of the object's content files against
+// the manifest or, with WithAlg, the inventory's fixity values.
+func (obj *ObjectReader) ValidateContent(ctx context.Context, opts ...ContentOption) (ValidationResult, error) {
+	return (*internal.ObjectReader)(obj).ValidateContent(ctx, opts...)
+}
+
+// DescriptionSchemaVersion is the version of the ObjectDescription JSON
+// schema.
+const DescriptionSchemaVersion = internal.DescriptionSchemaVersion
+
+// ObjectDescription is a self-contained description of an object returned
+// by Describe.
+type ObjectDescription = internal.ObjectDescription
+
+// VersionDescription describes a version in an ObjectDescription.
+type VersionDescription = internal.VersionDescription
+
+// FileDescription describes a logical file in a VersionDescription.
+type FileDescription = internal.FileDescription
+
+// FixityDescription summarizes a fixity block in an ObjectDescription.
+type FixityDescription = internal.FixityDescription
+
+// DescribeOption configures ObjectReader.Describe
+type DescribeOption = internal.DescribeOption
+
+// WithoutDescribeSizes configures Describe to omit file sizes.
+func WithoutDescribeSizes() DescribeOption {
+	return internal.WithoutDescribeSizes()
+}
+
+// WithDescribeHistory configures Describe to include the object's event
+// history.
+func WithDescribeHistory() DescribeOption {
+	return internal.WithDescribeHistory()
+}
+
+// Describe returns an ObjectDescription for the object.
+func (obj *ObjectReader) Describe(ctx context.Context, opts ...DescribeOption) (*ObjectDescription, error) {
+	return (*internal.ObjectReader)(obj).Describe(ctx, opts...)
+}
+
+// DefaultMaxInventorySize is the default InventoryLimits.MaxSize
+const DefaultMaxInventorySize = internal.DefaultMaxInventorySize
+
+// Inventory limits reported by InventoryLimitErr
+const (
+	LimitInventorySize = internal.LimitInventorySize
+	LimitPathLength    = internal.LimitPathLength
+	LimitPaths         = internal.LimitPaths
+)
+
+// ErrInventoryTooLarge indicates an inventory exceeded one of the
+// InventoryLimits.
+var ErrInventoryTooLarge = internal.ErrInventoryTooLarge
+
+// InventoryLimits protect against inventories that would use excessive
+// memory to read.
+type InventoryLimits = internal.InventoryLimits
+
+// InventoryLimitErr is returned when an inventory exceeds one of the
+// InventoryLimits.
+type InventoryLimitErr = internal.InventoryLimitErr
+
+// WithInventoryLimits sets limits on inventories read when opening or
+// validating an object.
+func WithInventoryLimits(limits InventoryLimits) ObjectOption {
+	return ObjectOption(internal.WithInventoryLimits(limits))
+}
+
+// ErrPathNotFound indicates a logical path isn't in any version state.
+var ErrPathNotFound = internal.ErrPathNotFound
+
+// PathHistory is the lifecycle of a logical path across an object's
+// versions. See Inventory.PathHistory and Inventory.AllPaths.
+type PathHistory = internal.PathHistory
+
+// PathRange is a contiguous range of versions in which a logical path had
+// the same digest.
+type PathRange = internal.PathRange
+
+// ErrContentDirIntroduced is the code for warnings about a contentDirectory
+// first set after the first version. It can be used with Profile.Promote.
+var ErrContentDirIntroduced = internal.ErrContentDirIntroduced
+
+// ErrObjectPathInvalid indicates an invalid object path passed to ObjectFS.
+var ErrObjectPathInvalid = internal.ErrObjectPathInvalid
+
+// ObjectPathErr is returned by ObjectFS for invalid object paths.
+type ObjectPathErr = internal.ObjectPathErr
+
+// ObjectFS returns an FS for the object in directory objPath of rootFS. The
+// path is validated, the directory must have an object declaration, and the
+// returned FS rejects names that could open files outside the object.
+func ObjectFS(rootFS fs.FS, objPath string) (fs.FS, error) {
+	return internal.ObjectFS(rootFS, objPath)
+}
+
+// WithLargestFirst configures content digesting to start with the largest
+// files, where file sizes are known before digesting begins.
+func WithLargestFirst() ObjectOption {
+	return ObjectOption(internal.WithLargestFirst())
+}
+
+// ErrInventoryMismatch indicates the root inventory and the head version's
+// inventory differ, or one of them doesn't match its sidecar.
+var ErrInventoryMismatch = internal.ErrInventoryMismatch
+
+// InventoryMismatchErr is returned by NewObjectReader with WithIntegrityCheck
+// if the root and head version inventories don't match.
+type InventoryMismatchErr = internal.InventoryMismatchErr
+
+// WithIntegrityCheck configures NewObjectReader to check that the root and
+// head version inventories are identical and match their sidecars. It can't
+// be used with WithInventoryFallback.
+func WithIntegrityCheck() ObjectOption {
+	return ObjectOption(internal.WithIntegrityCheck())
+}
+
+// Degraded returns true if the object was opened using a version inventory
+// because the root inventory could not be read.
+func (obj *ObjectReader) Degraded() bool {
+	return (*internal.ObjectReader)(obj).Degraded()
+}
+
+// DegradedErr returns the error reading the root inventory of a degraded
+// object.
+func (obj *ObjectReader) DegradedErr() error {
+	return (*internal.ObjectReader)(obj).DegradedErr()
+}
+
+// WithInventoryFallback allows NewObjectReader to open objects with a missing
+// or unreadable root inventory using the inventory from the highest valid
+// version directory.
+func WithInventoryFallback() ObjectOption {
+	return ObjectOption(internal.WithInventoryFallback())
+}
+
+// WithPermissiveParsing allows NewObjectReader to open objects with a CRLF or
+// missing newline in the object declaration, or a UTF-8 BOM in inventory
+// files. These are reported as validation warnings.
+func WithPermissiveParsing() ObjectOption {
+	return ObjectOption(internal.WithPermissiveParsing())
+}
+
+// WithoutSchemaValidation disables JSON schema validation of inventories
+// during validation.
+func WithoutSchemaValidation() ObjectOption {
+	return ObjectOption(internal.WithoutSchemaValidation())
+}
+
+// InventorySchemaErr is an inventory's violation of the OCFL inventory JSON
+// schema, with a JSON pointer to the invalid value.
+type InventorySchemaErr = internal.InventorySchemaErr
+
+// WithLenientSpec allows NewObjectReader to open objects declaring a newer
+// OCFL spec version for reading.
+func WithLenientSpec() ObjectOption {
+	return ObjectOption(internal.WithLenientSpec())
+}
+
+// User is a version's user entry.
+type User = internal.User
+
+// Kinds of user addresses returned by User.AddressKind
+const (
+	AddressNone  = internal.AddressNone
+	AddressEmail = internal.AddressEmail
+	AddressORCID = internal.AddressORCID
+	AddressURL   = internal.AddressURL
+	AddressOther = internal.AddressOther
+)
+
+// NewUser returns a User with a mailto: address for the email address.
+func NewUser(name string, email string) (User, error) {
+	return internal.NewUser(name, email)
+}
+
+// NewUserURI returns a User with a mailto, http, or https address URI.
+func NewUserURI(name string, uri string) (User, error) {
+	return internal.NewUserURI(name, uri)
+}
+
+// Profile configures the strictness of validation. See WithProfile.
+type Profile = internal.Profile
+
+// Check is a custom validation check that can be added to a Profile.
+type Check = internal.Check
+
+// CheckFunc is an adapter to allow the use of ordinary functions as Checks.
+type CheckFunc = internal.CheckFunc
+
+// ExtensionValidator validates the contents of an object extension
+// directory. See RegisterExtensionValidator.
+type ExtensionValidator = internal.ExtensionValidator
+
+// RegisterExtensionValidator sets the validator used for extension
+// directories named name. Errors from extension validators are reported as
+// warnings; extensions without a validator are reported with W013.
+func RegisterExtensionValidator(name string, v ExtensionValidator) {
+	internal.RegisterExtensionValidator(name, v)
+}
+
+// ValidationErr is an error returned from a validation check.
+type ValidationErr = internal.ValidationErr
+
+// OCFLCodeErr represents an OCFL validation code. Errors returned from
+// validation match their code with errors.Is:
+// errors.Is(err, ocfl.ErrContentChecksum)
+type OCFLCodeErr = internal.OCFLCodeErr
+
+// Common OCFL validation codes. Errors match codes by their Code value, so
+// these are copies: changing them doesn't affect validation. Other codes
+// can be matched with a new value: errors.Is(err, &ocfl.OCFLCodeErr{Code:
+// "E040"}).
+//
+// Content files are read once to check both the manifest and fixity, so
+// errors reading a content file are reported with ErrContentChecksum,
+// wrapping the cause. ErrFixityChecksum is only used when a digest doesn't
+// match a fixity value.
+var (
+	ErrDeclarationMissing = codeErr(internal.ErrE003) // object declaration not found
+	ErrInventoryMissing   = codeErr(internal.ErrE063) // root inventory not found
+	ErrSidecarMissing     = codeErr(internal.ErrE058) // inventory sidecar not found
+	ErrInventoryChecksum  = codeErr(internal.ErrE034) // inventory doesn't match sidecar
+	ErrContentChecksum    = codeErr(internal.ErrE092) // content doesn't match manifest
+	ErrFixityChecksum     = codeErr(internal.ErrE093) // content doesn't match fixity
+)
+
+// codeErr returns a pointer to a copy of code
+func codeErr(code internal.OCFLCodeErr) *OCFLCodeErr {
+	return &code
+}
+
+// Built-in validation profiles
+var (
+	DefaultProfile = internal.DefaultProfile
+	StrictProfile  = internal.StrictProfile
+)
+
+// WithProfile sets the validation profile used by ValidateObject.
+func WithProfile(p *Profile) ObjectOption {
+	return ObjectOption(internal.WithProfile(p))
+}
+
+// NewValidationErr returns a ValidationErr for use in custom Checks.
+func NewValidationErr(err error, code *OCFLCodeErr) ValidationErr {
+	return internal.NewValidationErr(err, code)
+}
+
+// Checkpoint records content files digested during validation.
+type Checkpoint = internal.Checkpoint
+
+// Checkpointer persists a Checkpoint so an interrupted validation can be
+// resumed. See WithCheckpoint.
+type Checkpointer = internal.Checkpointer
+
+// FileCheckpointer is a Checkpointer that saves checkpoints in a local file.
+type FileCheckpointer = internal.FileCheckpointer
+
+// WithCheckpoint configures ValidateObject to record digested content files
+// with cp, and to skip files recorded by an earlier, interrupted validation
+// of the same root inventory.
+func WithCheckpoint(cp Checkpointer) ObjectOption {
+	return ObjectOption(internal.WithCheckpoint(cp))
+}
+
+// WithCheckpointInterval sets how often the checkpoint is saved: after files
+// digests or after d has elapsed.
+func WithCheckpointInterval(files int, d time.Duration) ObjectOption {
+	return ObjectOption(internal.WithCheckpointInterval(files, d))
+}
+
+// WithMetrics sets the Metrics used to instrument validation and content
+// digesting.
+func WithMetrics(m Metrics) ObjectOption {
+	return ObjectOption(internal.WithMetrics(m))
+}
+
 // NewObjectReader returns an ObjectReader with root at fsys.
-func NewObjectReader(fsys fs.FS) (*ObjectReader, error) {
-	obj, err := internal.NewObjectReader(fsys)
+func NewObjectReader(fsys fs.FS, opts ...ObjectOption) (*ObjectReader, error) {
+	obj, err := internal.NewObjectReader(fsys, internalOpts(opts)...)
 	if err != nil {
 		return nil, err
 	}
@@ -25,6 +786,21 @@ func NewObjectReader(fsys fs.FS) (*ObjectReader, error) {
 }
 
 // ValidateObject returns ValidationResults for object at fsys.
-func ValidateObject(fsys fs.FS) ValidationResult {
-	return internal.ValidateObject(fsys)
+func ValidateObject(fsys fs.FS, opts ...ObjectOption) ValidationResult {
+	return internal.ValidateObject(fsys, internalOpts(opts)...)
+}
+
+// ValidateVersionDir validates the version directory dir in the object root
+// fsys without the rest of the object. Content from other versions is
+// reported as unverifiable with warnings.
+func ValidateVersionDir(ctx context.Context, fsys fs.FS, dir string) (ValidationResult, error) {
+	return internal.ValidateVersionDir(ctx, fsys, dir)
+}
+
+func internalOpts(opts []ObjectOption) []internal.ObjectOption {
+	iopts := make([]internal.ObjectOption, len(opts))
+	for i, o := range opts {
+		iopts[i] = internal.ObjectOption(o)
+	}
+	return iopts
 }
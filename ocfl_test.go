@@ -1,6 +1,8 @@
 package ocfl_test
 
 import (
+	"context"
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -61,3 +63,45 @@ func TestValidateObject(t *testing.T) {
 		t.Errorf("expected nil object to be invalid")
 	}
 }
+
+func TestValidationCodes(t *testing.T) {
+	badObj := filepath.Join(badObjPath, `E092_content_file_digest_mismatch`)
+	result := ocfl.ValidateObject(os.DirFS(badObj))
+	if result.Valid() {
+		t.Fatalf("expected %s to be invalid", badObj)
+	}
+	err := result.Fatal()[0]
+	if !errors.Is(err, ocfl.ErrContentChecksum) {
+		t.Errorf("expected ErrContentChecksum, got %v", err)
+	}
+	// changing an exported code doesn't change the codes used in validation
+	code := ocfl.ErrContentChecksum.Code
+	defer func() { ocfl.ErrContentChecksum.Code = code }()
+	ocfl.ErrContentChecksum.Code = `E000`
+	result = ocfl.ValidateObject(os.DirFS(badObj))
+	if err := result.Fatal()[0]; err.Code() != code {
+		t.Errorf("expected %s, got %v", code, err)
+	}
+}
+
+func TestObjectReaderContext(t *testing.T) {
+	obj, err := ocfl.NewObjectReader(os.DirFS(filepath.Join(goodObjPath, `spec-ex-full`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := obj.ContentContext(context.Background())
+	if err != nil || len(content) != 4 {
+		t.Errorf("unexpected content: %v, %v", content, err)
+	}
+	if result := obj.ValidateContext(context.Background()); !result.Valid() {
+		t.Fatal(result.Fatal())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := obj.ContentContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ContentContext: expected context.Canceled, got %v", err)
+	}
+	if result := obj.ValidateContext(ctx); result.Valid() {
+		t.Error("ValidateContext: expected canceled validation to fail")
+	}
+}
@@ -0,0 +1,87 @@
+package ocfltest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/srerickson/ocfl"
+)
+
+// TestBackend is a conformance test for storage backends. fsys must be the
+// root of a valid OCFL object and files must be some of the files in it.
+// TestBackend checks that fsys is a valid fs.FS (see fstest.TestFS), that
+// the object validates, and that the features declared by its Capabilities
+// work for each file.
+func TestBackend(t *testing.T, fsys fs.FS, files ...string) {
+	t.Helper()
+	if len(files) == 0 {
+		t.Fatal("TestBackend requires at least one file")
+	}
+	if err := fstest.TestFS(fsys, files...); err != nil {
+		t.Error(err)
+	}
+	if result := ocfl.ValidateObject(fsys); !result.Valid() {
+		t.Errorf("object isn't valid: %v", result.Fatal())
+	}
+	caps := ocfl.CapabilitiesOf(fsys)
+	for _, name := range files {
+		if caps.MaxKeyLength > 0 && len(name) > caps.MaxKeyLength {
+			t.Errorf("%s is longer than MaxKeyLength (%d)", name, caps.MaxKeyLength)
+		}
+		if caps.SupportsRangeReads {
+			testRangeReads(t, fsys, name)
+		}
+	}
+	ctxFS, isCtxFS := fsys.(ocfl.OpenContextFS)
+	if caps.SupportsContext {
+		if !isCtxFS {
+			t.Fatal("SupportsContext is set, but the backend doesn't implement OpenContextFS")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		f, err := ctxFS.OpenContext(ctx, files[0])
+		if err == nil {
+			f.Close()
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("OpenContext with canceled context: expected context.Canceled, got %v", err)
+		}
+	}
+}
+
+// testRangeReads checks that name implements io.ReaderAt and that ReadAt
+// returns the same bytes as Read.
+func testRangeReads(t *testing.T, fsys fs.FS, name string) {
+	t.Helper()
+	expected, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer f.Close()
+	readerAt, ok := f.(io.ReaderAt)
+	if !ok {
+		t.Errorf("SupportsRangeReads is set, but %s doesn't implement io.ReaderAt", name)
+		return
+	}
+	off := len(expected) / 2
+	got := make([]byte, len(expected)-off)
+	n, err := readerAt.ReadAt(got, int64(off))
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(got)) {
+		t.Errorf("ReadAt(%s, %d): %v", name, off, err)
+		return
+	}
+	if !bytes.Equal(got[:n], expected[off:]) {
+		t.Errorf("ReadAt(%s, %d) returned different bytes than Read", name, off)
+	}
+}
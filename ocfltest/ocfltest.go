@@ -0,0 +1,366 @@
+// Package ocfltest generates OCFL objects for testing code that uses the ocfl
+// package.
+package ocfltest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/rand"
+	"path"
+	"sort"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/srerickson/ocfl"
+	"github.com/srerickson/ocfl/internal"
+)
+
+const (
+	declaration   = `0=ocfl_object_1.0`
+	inventoryFile = `inventory.json`
+	inventoryType = `https://ocfl.io/1.0/spec/#inventory`
+)
+
+// Object is a generated object. FS is the object root; it can be modified
+// with Corrupt.
+type Object struct {
+	*ocfl.ObjectReader
+	FS fstest.MapFS
+}
+
+// Option configures NewObject
+type Option func(*config)
+
+type config struct {
+	versions int
+	files    int
+	minSize  int
+	maxSize  int
+	alg      string
+	dedup    float64
+	seed     int64
+}
+
+// WithVersions sets the number of versions. The default is 3.
+func WithVersions(n int) Option {
+	return func(c *config) {
+		c.versions = n
+	}
+}
+
+// WithFiles sets the number of files added in each version. The default is 4.
+func WithFiles(n int) Option {
+	return func(c *config) {
+		c.files = n
+	}
+}
+
+// WithSizes sets the range of file sizes, in bytes. Sizes are uniformly
+// distributed between min and max. The default is 0 to 1024.
+func WithSizes(min, max int) Option {
+	return func(c *config) {
+		c.minSize = min
+		c.maxSize = max
+	}
+}
+
+// WithDigestAlgorithm sets the object's digest algorithm: "sha512" (the
+// default) or "sha256".
+func WithDigestAlgorithm(alg string) Option {
+	return func(c *config) {
+		c.alg = alg
+	}
+}
+
+// WithDedup sets the fraction of files, after the first version, that
+// duplicate content from earlier versions. The default is 0.
+func WithDedup(ratio float64) Option {
+	return func(c *config) {
+		c.dedup = ratio
+	}
+}
+
+// WithSeed sets the random seed. Objects generated with the same options and
+// seed are identical. The default is 1.
+func WithSeed(seed int64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+// NewObject generates a valid object in memory. It calls t.Fatal if the
+// object can't be generated.
+func NewObject(t testing.TB, opts ...Option) *Object {
+	t.Helper()
+	conf := config{
+		versions: 3,
+		files:    4,
+		maxSize:  1024,
+		alg:      `sha512`,
+		seed:     1,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	fsys, err := generate(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := ocfl.NewObjectReader(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Object{ObjectReader: obj, FS: fsys}
+}
+
+func generate(conf config) (fstest.MapFS, error) {
+	newHash, err := hashFunc(conf.alg)
+	if err != nil {
+		return nil, err
+	}
+	if conf.versions < 1 {
+		return nil, errors.New(`objects must have at least one version`)
+	}
+	if conf.minSize < 0 || conf.maxSize < conf.minSize {
+		return nil, fmt.Errorf("invalid file size range: %d-%d", conf.minSize, conf.maxSize)
+	}
+	gen := rand.New(rand.NewSource(conf.seed))
+	fsys := fstest.MapFS{
+		declaration: &fstest.MapFile{Data: []byte("ocfl_object_1.0\n")},
+	}
+	inv := &internal.Inventory{
+		ID:               fmt.Sprintf("info:ocfltest/%d", conf.seed),
+		Type:             inventoryType,
+		DigestAlgorithm:  conf.alg,
+		ContentDirectory: `content`,
+		Manifest:         internal.DigestMap{},
+		Versions:         map[string]*internal.Version{},
+	}
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := internal.DigestMap{}
+	var digests []string // manifest digests, in order added
+	for v := 1; v <= conf.versions; v++ {
+		vname := fmt.Sprintf("v%d", v)
+		state = copyDigestMap(state)
+		for i := 0; i < conf.files; i++ {
+			logical := fmt.Sprintf("dir-%d/file-%d-%d.dat", i%3, v, i)
+			if len(digests) > 0 && gen.Float64() < conf.dedup {
+				digest := digests[gen.Intn(len(digests))]
+				state[digest] = append(state[digest], logical)
+				continue
+			}
+			size := conf.minSize
+			if conf.maxSize > conf.minSize {
+				size += gen.Intn(conf.maxSize - conf.minSize + 1)
+			}
+			data := make([]byte, size)
+			gen.Read(data)
+			h := newHash()
+			h.Write(data)
+			digest := hex.EncodeToString(h.Sum(nil))
+			state[digest] = append(state[digest], logical)
+			if _, exists := inv.Manifest[digest]; exists {
+				continue
+			}
+			contentPath := path.Join(vname, `content`, logical)
+			inv.Manifest[digest] = []string{contentPath}
+			digests = append(digests, digest)
+			fsys[contentPath] = &fstest.MapFile{Data: data}
+		}
+		inv.Head = vname
+		inv.Versions[vname] = &internal.Version{
+			Created: created.Add(time.Duration(v) * time.Hour),
+			State:   state,
+			Message: fmt.Sprintf("version %d", v),
+			User: internal.User{
+				Name:    `ocfltest`,
+				Address: `mailto:ocfltest@example.org`,
+			},
+		}
+		if err := writeInventory(fsys, vname, inv); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeInventory(fsys, `.`, inv); err != nil {
+		return nil, err
+	}
+	return fsys, nil
+}
+
+// writeInventory writes inv and its sidecar to dir in fsys
+func writeInventory(fsys fstest.MapFS, dir string, inv *internal.Inventory) error {
+	newHash, err := hashFunc(inv.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return err
+	}
+	h := newHash()
+	h.Write(data)
+	sidecar := hex.EncodeToString(h.Sum(nil)) + " " + inventoryFile + "\n"
+	fsys[path.Join(dir, inventoryFile)] = &fstest.MapFile{Data: data}
+	fsys[path.Join(dir, inventoryFile+"."+inv.DigestAlgorithm)] = &fstest.MapFile{Data: []byte(sidecar)}
+	return nil
+}
+
+func hashFunc(alg string) (func() hash.Hash, error) {
+	switch alg {
+	case `sha512`:
+		return sha512.New, nil
+	case `sha256`:
+		return sha256.New, nil
+	}
+	return nil, fmt.Errorf("unsupported digest algorithm: %s", alg)
+}
+
+func copyDigestMap(dm internal.DigestMap) internal.DigestMap {
+	cp := make(internal.DigestMap, len(dm))
+	for d, paths := range dm {
+		cp[d] = append([]string{}, paths...)
+	}
+	return cp
+}
+
+// Defect is a known defect that Corrupt can introduce in an object. Defects
+// can be combined: CorruptContentFile|CorruptSidecar.
+type Defect uint
+
+const (
+	// CorruptContentFile changes the content of a file in v1 so it doesn't
+	// match its manifest digest (E092).
+	CorruptContentFile Defect = 1 << iota
+	// CorruptSidecar replaces the root inventory sidecar's digest (E034).
+	CorruptSidecar
+	// DropManifestEntry removes an entry from the manifest of the root and
+	// head version inventories, leaving a state digest that isn't in the
+	// manifest (E050).
+	DropManifestEntry
+	// EditPriorState renames a logical path in the v1 state of the root and
+	// head version inventories, so it differs from the earlier version
+	// inventories (E066). The object must have at least two versions.
+	EditPriorState
+)
+
+// Corrupt introduces defects in obj.FS. Defects that modify the root
+// inventory also rewrite the head version's inventory, so the two match, and
+// update both sidecars. CorruptSidecar is applied to the root inventory
+// sidecar afterwards. obj.ObjectReader is not updated; use ocfl.ValidateObject
+// with obj.FS to check the result.
+func Corrupt(obj *Object, defects Defect) error {
+	if defects&CorruptContentFile != 0 {
+		if err := corruptContentFile(obj.FS); err != nil {
+			return err
+		}
+	}
+	if defects&(DropManifestEntry|EditPriorState) != 0 {
+		inv, err := readInventory(obj.FS)
+		if err != nil {
+			return err
+		}
+		if defects&DropManifestEntry != 0 {
+			if err := dropManifestEntry(inv); err != nil {
+				return err
+			}
+		}
+		if defects&EditPriorState != 0 {
+			if err := editPriorState(inv); err != nil {
+				return err
+			}
+		}
+		// the head version's inventory must match the root inventory
+		for _, dir := range []string{`.`, inv.Head} {
+			if err := writeInventory(obj.FS, dir, inv); err != nil {
+				return err
+			}
+		}
+	}
+	if defects&CorruptSidecar != 0 {
+		inv, err := readInventory(obj.FS)
+		if err != nil {
+			return err
+		}
+		sidecar := inventoryFile + "." + inv.DigestAlgorithm
+		size := bytes.IndexByte(obj.FS[sidecar].Data, ' ')
+		if size < 0 {
+			return errors.New(`invalid inventory sidecar`)
+		}
+		zeros := bytes.Repeat([]byte("0"), size)
+		obj.FS[sidecar] = &fstest.MapFile{Data: append(zeros, []byte(" "+inventoryFile+"\n")...)}
+	}
+	return nil
+}
+
+func corruptContentFile(fsys fstest.MapFS) error {
+	var names []string
+	for name := range fsys {
+		if len(name) > 11 && name[:11] == `v1/content/` {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return errors.New(`no content in v1 to corrupt`)
+	}
+	sort.Strings(names)
+	f := fsys[names[0]]
+	data := append([]byte{}, f.Data...)
+	if len(data) == 0 {
+		data = []byte{0}
+	} else {
+		data[0] ^= 0xff
+	}
+	fsys[names[0]] = &fstest.MapFile{Data: data}
+	return nil
+}
+
+func dropManifestEntry(inv *internal.Inventory) error {
+	digests := make([]string, 0, len(inv.Manifest))
+	for d := range inv.Manifest {
+		digests = append(digests, d)
+	}
+	if len(digests) == 0 {
+		return errors.New(`manifest is empty`)
+	}
+	sort.Strings(digests)
+	delete(inv.Manifest, digests[0])
+	return nil
+}
+
+func editPriorState(inv *internal.Inventory) error {
+	if inv.Head == `v1` {
+		return errors.New(`object has no prior versions`)
+	}
+	v1 := inv.Versions[`v1`]
+	digests := make([]string, 0, len(v1.State))
+	for d := range v1.State {
+		digests = append(digests, d)
+	}
+	if len(digests) == 0 {
+		return errors.New(`v1 state is empty`)
+	}
+	sort.Strings(digests)
+	state := copyDigestMap(v1.State)
+	state[digests[0]][0] = `edited-` + path.Base(state[digests[0]][0])
+	v1.State = state
+	return nil
+}
+
+func readInventory(fsys fstest.MapFS) (*internal.Inventory, error) {
+	f, ok := fsys[inventoryFile]
+	if !ok {
+		return nil, errors.New(`root inventory not found`)
+	}
+	var inv internal.Inventory
+	if err := json.Unmarshal(f.Data, &inv); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
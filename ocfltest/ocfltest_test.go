@@ -0,0 +1,92 @@
+package ocfltest_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/srerickson/ocfl"
+	"github.com/srerickson/ocfl/ocfltest"
+)
+
+func TestNewObject(t *testing.T) {
+	table := map[string][]ocfltest.Option{
+		`default`: nil,
+		`sha256`:  {ocfltest.WithDigestAlgorithm(`sha256`)},
+		`large`:   {ocfltest.WithVersions(5), ocfltest.WithFiles(20), ocfltest.WithSizes(0, 4096)},
+		`dedup`:   {ocfltest.WithDedup(0.5), ocfltest.WithSizes(0, 2)},
+		`one`:     {ocfltest.WithVersions(1), ocfltest.WithFiles(1)},
+	}
+	for name, opts := range table {
+		t.Run(name, func(t *testing.T) {
+			obj := ocfltest.NewObject(t, opts...)
+			result := ocfl.ValidateObject(obj.FS)
+			if !result.Valid() {
+				t.Fatal(result.Fatal())
+			}
+		})
+	}
+}
+
+func TestNewObjectSeed(t *testing.T) {
+	a := ocfltest.NewObject(t, ocfltest.WithSeed(42))
+	b := ocfltest.NewObject(t, ocfltest.WithSeed(42))
+	c := ocfltest.NewObject(t, ocfltest.WithSeed(43))
+	if string(a.FS[`inventory.json`].Data) != string(b.FS[`inventory.json`].Data) {
+		t.Error("objects with the same seed should be identical")
+	}
+	if string(a.FS[`inventory.json`].Data) == string(c.FS[`inventory.json`].Data) {
+		t.Error("objects with different seeds should differ")
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	table := map[string]struct {
+		defect ocfltest.Defect
+		codes  []string
+	}{
+		`content`:  {ocfltest.CorruptContentFile, []string{`E092`}},
+		`sidecar`:  {ocfltest.CorruptSidecar, []string{`E034`}},
+		`manifest`: {ocfltest.DropManifestEntry, []string{`E050`}},
+		`state`:    {ocfltest.EditPriorState, []string{`E066`}},
+		`combined`: {ocfltest.CorruptContentFile | ocfltest.EditPriorState, []string{`E066`, `E092`}},
+	}
+	for name, tcase := range table {
+		t.Run(name, func(t *testing.T) {
+			obj := ocfltest.NewObject(t)
+			if err := ocfltest.Corrupt(obj, tcase.defect); err != nil {
+				t.Fatal(err)
+			}
+			result := ocfl.ValidateObject(obj.FS)
+			if result.Valid() {
+				t.Fatal("expected corrupted object to be invalid")
+			}
+			found := map[string]bool{}
+			for _, err := range result.Fatal() {
+				found[err.Code()] = true
+			}
+			expected := map[string]bool{}
+			for _, code := range tcase.codes {
+				expected[code] = true
+			}
+			if !reflect.DeepEqual(found, expected) {
+				t.Errorf("expected codes %v, got %v", tcase.codes, result.Fatal())
+			}
+		})
+	}
+}
+
+func TestTestBackend(t *testing.T) {
+	t.Run("MapFS", func(t *testing.T) {
+		obj := ocfltest.NewObject(t, ocfltest.WithVersions(2), ocfltest.WithFiles(3))
+		ocfltest.TestBackend(t, obj.FS, `inventory.json`, `v1/content/dir-0/file-1-0.dat`)
+	})
+	t.Run("DirFS", func(t *testing.T) {
+		fsys := os.DirFS(filepath.Join(`..`, `test`, `fixtures`, `1.0`, `good-objects`, `spec-ex-full`))
+		if caps := ocfl.CapabilitiesOf(fsys); caps != (ocfl.Capabilities{}) {
+			t.Errorf("unexpected capabilities: %+v", caps)
+		}
+		ocfltest.TestBackend(t, fsys, `inventory.json`, `v1/content/foo/bar.xml`)
+	})
+}